@@ -0,0 +1,188 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	v1 "github.com/kubescape/backend/pkg/server/v1"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+var (
+	_ IAuthenticator = &StaticKeyAuthenticator{}
+	_ IAuthenticator = &TokenFileAuthenticator{}
+	_ IAuthenticator = &OAuth2Authenticator{}
+
+	// ErrEmptyToken is returned when a bearer token file is empty.
+	ErrEmptyToken = errors.New("empty bearer token")
+)
+
+// IAuthenticator decorates outgoing requests to the KS Cloud API with credentials.
+//
+// Authenticate is called before every attempt to send a request, so that short-lived credentials
+// may be refreshed. Implementations must be safe for concurrent use.
+type IAuthenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// StaticKeyAuthenticator authenticates requests with an API access key.
+//
+// Without an authenticator, the client already sends its access key: this is useful to share a key between clients.
+// The key may be rotated with SetAccessKey, which a client configured with this authenticator forwards to it.
+type StaticKeyAuthenticator struct {
+	mu        sync.RWMutex
+	accessKey string
+}
+
+// NewStaticKeyAuthenticator builds an authenticator sending a static API access key.
+func NewStaticKeyAuthenticator(accessKey string) *StaticKeyAuthenticator {
+	return &StaticKeyAuthenticator{accessKey: accessKey}
+}
+
+// SetAccessKey replaces the access key sent with subsequent requests.
+func (a *StaticKeyAuthenticator) SetAccessKey(accessKey string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.accessKey = accessKey
+}
+
+// Authenticate sets the access key header.
+func (a *StaticKeyAuthenticator) Authenticate(req *http.Request) error {
+	a.mu.RLock()
+	accessKey := a.accessKey
+	a.mu.RUnlock()
+
+	if accessKey != "" {
+		req.Header.Set(v1.AccessKeyHeader, accessKey)
+	}
+
+	return nil
+}
+
+// TokenFileAuthenticator authenticates requests with a bearer token read from a file,
+// such as a projected service account token.
+//
+// The file is read again whenever it changes, so that rotated tokens are picked up.
+type TokenFileAuthenticator struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewTokenFileAuthenticator builds an authenticator sending the bearer token held in a file.
+func NewTokenFileAuthenticator(path string) *TokenFileAuthenticator {
+	return &TokenFileAuthenticator{path: path}
+}
+
+// Authenticate sets the Authorization header with the current token.
+func (a *TokenFileAuthenticator) Authenticate(req *http.Request) error {
+	token, err := a.Token()
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+// Token yields the current token, reading the file again if it has changed since the last read.
+func (a *TokenFileAuthenticator) Token() (string, error) {
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return "", fmt.Errorf("reading bearer token: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && info.ModTime().Equal(a.modTime) && info.Size() == a.size {
+		return a.token, nil
+	}
+
+	buf, err := os.ReadFile(a.path)
+	if err != nil {
+		return "", fmt.Errorf("reading bearer token: %w", err)
+	}
+
+	token := string(bytes.TrimSpace(buf))
+	if token == "" {
+		return "", fmt.Errorf("%w: %s", ErrEmptyToken, a.path)
+	}
+
+	a.token = token
+	a.modTime = info.ModTime()
+	a.size = info.Size()
+
+	return a.token, nil
+}
+
+// OAuth2Config configures the OAuth2 client credentials flow.
+type OAuth2Config struct {
+	// TokenURL is the token endpoint of the authorization server.
+	TokenURL string
+
+	// ClientID and ClientSecret identify the client.
+	ClientID     string
+	ClientSecret string
+
+	// Scopes optionally requests specific scopes.
+	Scopes []string
+
+	// EndpointParams holds additional parameters sent to the token endpoint, e.g. an audience.
+	EndpointParams map[string][]string
+
+	// HTTPClient is used to call the token endpoint. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// OAuth2Authenticator authenticates requests with an access token obtained with the OAuth2
+// client credentials flow.
+//
+// Tokens are cached and a new token is requested shortly before the current one expires.
+type OAuth2Authenticator struct {
+	source oauth2.TokenSource
+}
+
+// NewOAuth2Authenticator builds an authenticator using the OAuth2 client credentials flow.
+func NewOAuth2Authenticator(config OAuth2Config) *OAuth2Authenticator {
+	ccConfig := &clientcredentials.Config{
+		ClientID:       config.ClientID,
+		ClientSecret:   config.ClientSecret,
+		TokenURL:       config.TokenURL,
+		Scopes:         config.Scopes,
+		EndpointParams: config.EndpointParams,
+	}
+
+	ctx := context.Background()
+	if config.HTTPClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, config.HTTPClient)
+	}
+
+	return &OAuth2Authenticator{
+		source: ccConfig.TokenSource(ctx),
+	}
+}
+
+// Authenticate sets the Authorization header with a valid access token.
+func (a *OAuth2Authenticator) Authenticate(req *http.Request) error {
+	token, err := a.source.Token()
+	if err != nil {
+		return fmt.Errorf("obtaining OAuth2 token: %w", err)
+	}
+
+	token.SetAuthHeader(req)
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	backendServer "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticators(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should send the access key by default", func(t *testing.T) {
+		t.Parallel()
+
+		var accessKey atomic.Value
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accessKey.Store(r.Header.Get(backendServer.AccessKeyHeader))
+			_ = json.NewEncoder(w).Encode(mockAttackTracks())
+		}))
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "static-key")
+		require.NoError(t, err)
+
+		_, err = ks.GetAttackTracks()
+		require.NoError(t, err)
+		require.Equal(t, "static-key", accessKey.Load())
+
+		// the access key may be rotated
+		ks.SetAccessKey("rotated-key")
+		_, err = ks.GetAttackTracks()
+		require.NoError(t, err)
+		require.Equal(t, "rotated-key", accessKey.Load())
+
+		ks, err = NewKSCloudAPI(srv.URL, srv.URL, "account", "ignored-key",
+			WithAuthenticator(NewStaticKeyAuthenticator("other-key")),
+		)
+		require.NoError(t, err)
+
+		_, err = ks.GetAttackTracks()
+		require.NoError(t, err)
+		require.Equal(t, "other-key", accessKey.Load())
+
+		// the access key of the authenticator may be rotated as well
+		ks.SetAccessKey("rotated-other-key")
+		_, err = ks.GetAttackTracks()
+		require.NoError(t, err)
+		require.Equal(t, "rotated-other-key", accessKey.Load())
+	})
+
+	t.Run("should send a bearer token read from a file", func(t *testing.T) {
+		t.Parallel()
+
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("token-1\n"), 0o600))
+
+		srv := MockAPIServer(t, withAPIAuth(true))
+		t.Cleanup(srv.Close)
+
+		authenticator := NewTokenFileAuthenticator(tokenFile)
+		ks, err := NewKSCloudAPI(srv.Root(), srv.Root(), "account", "", WithAuthenticator(authenticator))
+		require.NoError(t, err)
+
+		_, err = ks.GetFrameworks()
+		require.NoError(t, err)
+
+		token, err := authenticator.Token()
+		require.NoError(t, err)
+		require.Equal(t, "token-1", token)
+
+		// the token is rotated
+		require.NoError(t, os.WriteFile(tokenFile, []byte("token-22"), 0o600))
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(tokenFile, later, later))
+
+		token, err = authenticator.Token()
+		require.NoError(t, err)
+		require.Equal(t, "token-22", token)
+	})
+
+	t.Run("should fail on a missing or empty token file", func(t *testing.T) {
+		t.Parallel()
+
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		authenticator := NewTokenFileAuthenticator(tokenFile)
+
+		_, err := authenticator.Token()
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		require.NoError(t, os.WriteFile(tokenFile, []byte("  \n"), 0o600))
+		_, err = authenticator.Token()
+		require.ErrorIs(t, err, ErrEmptyToken)
+
+		ks, err := NewKSCloudAPI("api.example.com", "report.example.com", "account", "", WithAuthenticator(authenticator))
+		require.NoError(t, err)
+
+		_, err = ks.GetAttackTracks()
+		require.ErrorIs(t, err, ErrEmptyToken)
+
+		// the body of the request which is not sent is closed
+		body := &closeRecorder{Reader: strings.NewReader("{}")}
+		req, err := http.NewRequest(http.MethodPost, ks.postReportURL(ks.current(), "cluster", "report"), body)
+		require.NoError(t, err)
+
+		_, err = ks.send(req, ks.defaultRequestOptions(context.Background(), nil))
+		require.ErrorIs(t, err, ErrEmptyToken)
+		require.True(t, body.closed.Load())
+	})
+
+	t.Run("should obtain and cache OAuth2 tokens", func(t *testing.T) {
+		t.Parallel()
+
+		var tokenCalls atomic.Int32
+		tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenCalls.Add(1)
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "oauth-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+		}))
+		t.Cleanup(tokenSrv.Close)
+
+		var authorization atomic.Value
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization.Store(r.Header.Get("Authorization"))
+			_ = json.NewEncoder(w).Encode(mockAttackTracks())
+		}))
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "",
+			WithAuthenticator(NewOAuth2Authenticator(OAuth2Config{
+				TokenURL:     tokenSrv.URL,
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				Scopes:       []string{"posture"},
+			})),
+		)
+		require.NoError(t, err)
+
+		for range 3 {
+			_, err = ks.GetAttackTracks()
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, "Bearer oauth-token", authorization.Load())
+		require.Equal(t, int32(1), tokenCalls.Load())
+	})
+}
@@ -0,0 +1,367 @@
+package v1
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/kubescape/backend/pkg/utils"
+)
+
+const (
+	// BundleFormatVersion is the version of the offline bundle layout produced by ExportBundle.
+	BundleFormatVersion = 1
+
+	bundleManifestFile       = "manifest.json"
+	bundleFrameworksFile     = "frameworks.json"
+	bundleAttackTracksFile   = "attackTracks.json"
+	bundleExceptionsFile     = "exceptions.json"
+	bundleCustomerConfigFile = "customerConfig.json"
+
+	// maxBundleFileSize caps the size of a single file read from a bundle.
+	maxBundleFileSize = 512 << 20
+)
+
+var (
+	ErrBundleChecksum    = errors.New("bundle checksum mismatch")
+	ErrBundleVersion     = errors.New("unsupported bundle format version")
+	ErrBundleMissingFile = errors.New("missing file in bundle")
+)
+
+type (
+	// BundleManifest describes the content of an offline policy bundle.
+	BundleManifest struct {
+		// Version is the bundle format version.
+		Version int `json:"version"`
+
+		// RegolibraryVersion is the version of the rego library the policies were fetched for.
+		RegolibraryVersion string `json:"regolibraryVersion"`
+
+		// FetchedAt is the time when the policies were fetched from the API.
+		FetchedAt time.Time `json:"fetchedAt"`
+
+		// AccountID is the customer account the policies were fetched for.
+		AccountID string `json:"accountID,omitempty"`
+
+		// ClusterName is the cluster the exceptions and account configuration were fetched for.
+		ClusterName string `json:"clusterName,omitempty"`
+
+		// Checksums maps every file in the bundle to its hex-encoded SHA-256 checksum.
+		Checksums map[string]string `json:"checksums"`
+	}
+
+	// KSCloudBundle serves policies from an offline bundle, for air-gapped environments.
+	//
+	// It exposes the same getters as KSCloudAPI.
+	KSCloudBundle struct {
+		manifest       BundleManifest
+		frameworks     []Framework
+		attackTracks   []AttackTrack
+		exceptions     []PostureExceptionPolicy
+		customerConfig *CustomerConfig
+		controls       map[string]*Control
+		controlIDs     []string
+	}
+)
+
+// IsStale indicates if the bundle was fetched more than maxAge ago,
+// or for a rego library version that is not the current one.
+//
+// A maxAge of 0 only checks the rego library version.
+func (m BundleManifest) IsStale(maxAge time.Duration) bool {
+	if m.RegolibraryVersion != v1.RegolibraryVersion {
+		return true
+	}
+
+	return maxAge > 0 && time.Since(m.FetchedAt) > maxAge
+}
+
+// ExportBundle fetches frameworks, attack tracks, exceptions and the account configuration from the API,
+// and writes them as a checksummed, gzipped tar archive.
+func ExportBundle(ctx context.Context, api *KSCloudAPI, w io.Writer, clusterName string) (*BundleManifest, error) {
+	frameworks, err := api.GetFrameworksCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching frameworks: %w", err)
+	}
+
+	attackTracks, err := api.GetAttackTracksCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching attack tracks: %w", err)
+	}
+
+	exceptions, err := api.GetExceptionsCtx(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("fetching exceptions: %w", err)
+	}
+
+	customerConfig, err := api.GetAccountConfigCtx(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("fetching account configuration: %w", err)
+	}
+
+	files := make(map[string][]byte, 4)
+	for name, doc := range map[string]any{
+		bundleFrameworksFile:     frameworks,
+		bundleAttackTracksFile:   attackTracks,
+		bundleExceptionsFile:     exceptions,
+		bundleCustomerConfigFile: customerConfig,
+	} {
+		buf, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = buf
+	}
+
+	manifest := &BundleManifest{
+		Version:            BundleFormatVersion,
+		RegolibraryVersion: v1.RegolibraryVersion,
+		FetchedAt:          time.Now().UTC(),
+		AccountID:          api.GetAccountID(),
+		ClusterName:        clusterName,
+		Checksums:          make(map[string]string, len(files)),
+	}
+	for name, buf := range files {
+		manifest.Checksums[name] = checksum(buf)
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	// the manifest comes first, so readers may inspect it without reading the whole archive
+	if err := writeBundleFile(tw, bundleManifestFile, manifestJSON, manifest.FetchedAt); err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{bundleFrameworksFile, bundleAttackTracksFile, bundleExceptionsFile, bundleCustomerConfigFile} {
+		if err := writeBundleFile(tw, name, files[name], manifest.FetchedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// OpenBundle reads an offline bundle produced by ExportBundle, and verifies its checksums.
+func OpenBundle(r io.Reader) (*KSCloudBundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte, 5)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		buf, err := io.ReadAll(io.LimitReader(tr, maxBundleFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		files[header.Name] = buf
+	}
+
+	manifestJSON, ok := files[bundleManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBundleMissingFile, bundleManifestFile)
+	}
+
+	bundle := &KSCloudBundle{}
+	if err := json.Unmarshal(manifestJSON, &bundle.manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+
+	if bundle.manifest.Version != BundleFormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrBundleVersion, bundle.manifest.Version)
+	}
+
+	for _, name := range []string{bundleFrameworksFile, bundleAttackTracksFile, bundleExceptionsFile, bundleCustomerConfigFile} {
+		buf, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrBundleMissingFile, name)
+		}
+
+		if expected := bundle.manifest.Checksums[name]; expected != checksum(buf) {
+			return nil, fmt.Errorf("%w: %s", ErrBundleChecksum, name)
+		}
+	}
+
+	if bundle.frameworks, err = utils.Decode[[]Framework](bytes.NewReader(files[bundleFrameworksFile])); err != nil {
+		return nil, err
+	}
+
+	if bundle.attackTracks, err = utils.Decode[[]AttackTrack](bytes.NewReader(files[bundleAttackTracksFile])); err != nil {
+		return nil, err
+	}
+
+	if bundle.exceptions, err = utils.Decode[[]PostureExceptionPolicy](bytes.NewReader(files[bundleExceptionsFile])); err != nil {
+		return nil, err
+	}
+
+	customerConfig, err := utils.Decode[CustomerConfig](bytes.NewReader(files[bundleCustomerConfigFile]))
+	if err != nil {
+		return nil, err
+	}
+	bundle.customerConfig = &customerConfig
+	bundle.controls, bundle.controlIDs = indexControls(controlsFromFrameworks(bundle.frameworks))
+
+	return bundle, nil
+}
+
+// Manifest returns the manifest of the bundle.
+func (b *KSCloudBundle) Manifest() BundleManifest {
+	return b.manifest
+}
+
+// GetAccountID returns the customer account's GUID the bundle was fetched for.
+func (b *KSCloudBundle) GetAccountID() string { return b.manifest.AccountID }
+
+// GetAttackTracks returns all attack tracks in the bundle.
+func (b *KSCloudBundle) GetAttackTracks() ([]AttackTrack, error) {
+	return slices.Clone(b.attackTracks), nil
+}
+
+// GetFramework retrieves a framework from the bundle by name.
+func (b *KSCloudBundle) GetFramework(frameworkName string) (*Framework, error) {
+	for i := range b.frameworks {
+		if strings.EqualFold(b.frameworks[i].Name, frameworkName) {
+			framework := b.frameworks[i]
+
+			return &framework, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrFrameworkNotFound, frameworkName)
+}
+
+// GetFrameworks returns all frameworks in the bundle.
+func (b *KSCloudBundle) GetFrameworks() ([]Framework, error) {
+	return slices.Clone(b.frameworks), nil
+}
+
+// ListCustomFrameworks lists the names of all non-native frameworks in the bundle.
+func (b *KSCloudBundle) ListCustomFrameworks() ([]string, error) {
+	frameworkList := make([]string, 0, len(b.frameworks))
+	for _, framework := range b.frameworks {
+		if utils.IsNativeFramework(framework.Name) {
+			continue
+		}
+
+		frameworkList = append(frameworkList, framework.Name)
+	}
+
+	return frameworkList, nil
+}
+
+// ListFrameworks list the names of all frameworks in the bundle.
+func (b *KSCloudBundle) ListFrameworks() ([]string, error) {
+	frameworkList := make([]string, 0, len(b.frameworks))
+	for _, framework := range b.frameworks {
+		name := framework.Name
+		if utils.IsNativeFramework(framework.Name) {
+			name = strings.ToLower(framework.Name)
+		}
+
+		frameworkList = append(frameworkList, name)
+	}
+
+	return frameworkList, nil
+}
+
+// GetControl retrieves a control from the frameworks in the bundle, by ID.
+func (b *KSCloudBundle) GetControl(ID string) (*Control, error) {
+	control, ok := b.controls[ID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrControlNotFound, ID)
+	}
+
+	return cloneControl(control), nil
+}
+
+// ListControls lists the IDs of all controls held by the frameworks in the bundle.
+func (b *KSCloudBundle) ListControls() ([]string, error) {
+	return slices.Clone(b.controlIDs), nil
+}
+
+// GetExceptions returns the exception policies in the bundle.
+//
+// The cluster name is ignored: exceptions are those fetched for the cluster recorded in the manifest.
+func (b *KSCloudBundle) GetExceptions(_ string) ([]PostureExceptionPolicy, error) {
+	return slices.Clone(b.exceptions), nil
+}
+
+// GetAccountConfig yields the account configuration in the bundle.
+//
+// The cluster name is ignored: the configuration is the one fetched for the cluster recorded in the manifest.
+func (b *KSCloudBundle) GetAccountConfig(_ string) (*CustomerConfig, error) {
+	customerConfig := *b.customerConfig
+
+	return &customerConfig, nil
+}
+
+// GetControlsInputs returns the controls inputs configured in the account configuration in the bundle.
+func (b *KSCloudBundle) GetControlsInputs(clusterName string) (map[string][]string, error) {
+	accountConfig, err := b.GetAccountConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	return maps.Clone(accountConfig.Settings.PostureControlInputs), nil
+}
+
+func writeBundleFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+
+	_, err := tw.Write(content)
+
+	return err
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
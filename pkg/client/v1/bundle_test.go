@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+
+	srv := MockAPIServer(t)
+	t.Cleanup(srv.Close)
+
+	ks, err := NewKSCloudAPI(srv.Root(), srv.Root(), "account", "", testOptions...)
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	manifest, err := ExportBundle(context.Background(), ks, &archive, "my-cluster")
+	require.NoError(t, err)
+	require.Equal(t, BundleFormatVersion, manifest.Version)
+	require.Equal(t, "account", manifest.AccountID)
+	require.Len(t, manifest.Checksums, 4)
+	require.False(t, manifest.IsStale(time.Hour))
+
+	t.Run("should serve policies from the bundle", func(t *testing.T) {
+		t.Parallel()
+
+		bundle, err := OpenBundle(bytes.NewReader(archive.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, "my-cluster", bundle.Manifest().ClusterName)
+
+		frameworks, err := bundle.GetFrameworks()
+		require.NoError(t, err)
+		require.EqualValues(t, mockFrameworks(), frameworks)
+
+		framework, err := bundle.GetFramework("NSA")
+		require.NoError(t, err)
+		require.Equal(t, "nsa", framework.Name)
+
+		_, err = bundle.GetFramework("unknown")
+		require.Error(t, err)
+
+		names, err := bundle.ListCustomFrameworks()
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"mock-1", "mock-2"}, names)
+
+		exceptions, err := bundle.GetExceptions("")
+		require.NoError(t, err)
+		require.EqualValues(t, mockExceptions(), exceptions)
+
+		tracks, err := bundle.GetAttackTracks()
+		require.NoError(t, err)
+		require.Len(t, tracks, 2)
+
+		inputs, err := bundle.GetControlsInputs("")
+		require.NoError(t, err)
+		require.EqualValues(t, mockCustomerConfig("my-cluster", "")().Settings.PostureControlInputs, inputs)
+	})
+
+	t.Run("should not let callers alter the bundle", func(t *testing.T) {
+		t.Parallel()
+
+		bundle, err := OpenBundle(bytes.NewReader(archive.Bytes()))
+		require.NoError(t, err)
+
+		frameworks, err := bundle.GetFrameworks()
+		require.NoError(t, err)
+		frameworks[0].Name = "altered"
+
+		tracks, err := bundle.GetAttackTracks()
+		require.NoError(t, err)
+		tracks[0].Kind = "altered"
+
+		exceptions, err := bundle.GetExceptions("")
+		require.NoError(t, err)
+		exceptions[0].Name = "altered"
+
+		ids, err := bundle.ListControls()
+		require.NoError(t, err)
+		ids[0] = "altered"
+
+		control, err := bundle.GetControl("control-6")
+		require.NoError(t, err)
+		control.Name = "altered"
+		control.FrameworkNames[0] = "altered"
+
+		accountConfig, err := bundle.GetAccountConfig("")
+		require.NoError(t, err)
+		accountConfig.Name = "altered"
+
+		frameworks, err = bundle.GetFrameworks()
+		require.NoError(t, err)
+		require.EqualValues(t, mockFrameworks(), frameworks)
+
+		tracks, err = bundle.GetAttackTracks()
+		require.NoError(t, err)
+		require.NotEqual(t, "altered", tracks[0].Kind)
+
+		exceptions, err = bundle.GetExceptions("")
+		require.NoError(t, err)
+		require.EqualValues(t, mockExceptions(), exceptions)
+
+		ids, err = bundle.ListControls()
+		require.NoError(t, err)
+		require.NotContains(t, ids, "altered")
+
+		control, err = bundle.GetControl("control-6")
+		require.NoError(t, err)
+		require.NotEqual(t, "altered", control.Name)
+		require.Equal(t, []string{"nsa"}, control.FrameworkNames)
+
+		accountConfig, err = bundle.GetAccountConfig("")
+		require.NoError(t, err)
+		require.NotEqual(t, "altered", accountConfig.Name)
+	})
+
+	t.Run("should detect a corrupted bundle", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := rewriteBundle(t, archive.Bytes(), bundleExceptionsFile, []byte(`[]`))
+		_, err := OpenBundle(bytes.NewReader(tampered))
+		require.ErrorIs(t, err, ErrBundleChecksum)
+	})
+
+	t.Run("should detect a stale bundle", func(t *testing.T) {
+		t.Parallel()
+
+		stale := *manifest
+		stale.FetchedAt = time.Now().Add(-48 * time.Hour)
+		require.True(t, stale.IsStale(24*time.Hour))
+		require.False(t, stale.IsStale(0))
+
+		stale.RegolibraryVersion = "v0"
+		require.True(t, stale.IsStale(0))
+	})
+
+	t.Run("should reject an invalid archive", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := OpenBundle(bytes.NewReader([]byte("not a bundle")))
+		require.Error(t, err)
+	})
+}
+
+// rewriteBundle copies a bundle, replacing the content of a file.
+func rewriteBundle(t testing.TB, archive []byte, name string, content []byte) []byte {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	var out bytes.Buffer
+	gzw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gzw)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		buf, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if header.Name == name {
+			buf = content
+		}
+
+		require.NoError(t, writeBundleFile(tw, header.Name, buf, header.ModTime))
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+
+	return out.Bytes()
+}
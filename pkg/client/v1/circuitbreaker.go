@@ -0,0 +1,245 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request whenever the circuit breaker of a host is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all requests fast, until the cool-down period has elapsed.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through, to find out if the host has recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig configures a circuit breaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit. Defaults to 5.
+	FailureThreshold int
+
+	// CoolDown is the time the circuit remains open before probe requests are let through. Defaults to 30s.
+	CoolDown time.Duration
+
+	// HalfOpenMaxRequests is the number of concurrent probe requests allowed in the half-open state. Defaults to 1.
+	HalfOpenMaxRequests int
+
+	// IsFailure decides if a response or a network error counts as a failure of the host.
+	//
+	// Defaults to DefaultCircuitFailure.
+	IsFailure func(resp *http.Response, err error) bool
+
+	// OnStateChange is called whenever the circuit of a host changes state.
+	//
+	// The callback is invoked synchronously, from the goroutine sending a request or reading the state, once the breaker
+	// is unlocked: it may call State, but should not block. Changes reported from concurrent goroutines may interleave.
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// DefaultCircuitFailure counts network errors (except context cancellation or deadline)
+// and 5xx status codes as failures.
+func DefaultCircuitFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return resp != nil && resp.StatusCode >= http.StatusInternalServerError
+}
+
+// CircuitBreaker stops sending requests to a host after consecutive failures.
+//
+// Once open, requests fail fast with ErrCircuitOpen for the cool-down period. The circuit then becomes half-open:
+// a probe request is let through, which closes the circuit if it succeeds, or opens it again if it fails.
+//
+// A CircuitBreaker is safe for concurrent use, and may be shared by several clients of the same host.
+type CircuitBreaker struct {
+	host   string
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+
+	// generation is incremented on every state change: the outcome of a request allowed
+	// in an earlier generation (e.g. a slow request started before the circuit opened) is ignored.
+	generation uint64
+
+	// changes are the state changes not yet notified, which are notified once the lock is released
+	changes []circuitStateChange
+}
+
+// circuitStateChange is a state change of a circuit, pending notification.
+type circuitStateChange struct {
+	from, to CircuitState
+}
+
+// NewCircuitBreaker builds a circuit breaker for a host, in the closed state.
+func NewCircuitBreaker(host string, config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = 30 * time.Second
+	}
+	if config.HalfOpenMaxRequests <= 0 {
+		config.HalfOpenMaxRequests = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = DefaultCircuitFailure
+	}
+
+	return &CircuitBreaker{
+		host:   host,
+		config: config,
+	}
+}
+
+// Host yields the host protected by this circuit breaker.
+func (cb *CircuitBreaker) Host() string {
+	return cb.host
+}
+
+// State yields the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	return cb.currentState()
+}
+
+// allow checks if a request may be sent, and yields the generation of the circuit that admits it.
+//
+// Every allowed request must be followed by a call to record, with this generation.
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	switch cb.currentState() {
+	case CircuitOpen:
+		return 0, fmt.Errorf("%w: %s", ErrCircuitOpen, cb.host)
+	case CircuitHalfOpen:
+		if cb.probes >= cb.config.HalfOpenMaxRequests {
+			return 0, fmt.Errorf("%w: %s", ErrCircuitOpen, cb.host)
+		}
+		cb.probes++
+	}
+
+	return cb.generation, nil
+}
+
+// record accounts for the outcome of a request allowed in the given generation.
+//
+// Outcomes from an earlier generation are ignored.
+func (cb *CircuitBreaker) record(generation uint64, resp *http.Response, err error) {
+	failed := cb.config.IsFailure(resp, err)
+
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	state := cb.currentState()
+	if generation != cb.generation {
+		return
+	}
+
+	if state == CircuitHalfOpen && cb.probes > 0 {
+		cb.probes--
+	}
+
+	if !failed {
+		cb.failures = 0
+		if state != CircuitClosed {
+			cb.transition(state, CircuitClosed)
+		}
+
+		return
+	}
+
+	cb.failures++
+	if state == CircuitHalfOpen || (state == CircuitClosed && cb.failures >= cb.config.FailureThreshold) {
+		cb.openedAt = time.Now()
+		cb.transition(state, CircuitOpen)
+	}
+}
+
+// currentState yields the state of the circuit, moving from open to half-open once the cool-down period has elapsed.
+//
+// The caller must hold the lock.
+func (cb *CircuitBreaker) currentState() CircuitState {
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.config.CoolDown {
+		cb.probes = 0
+		cb.transition(CircuitOpen, CircuitHalfOpen)
+	}
+
+	return cb.state
+}
+
+// transition changes the state of the circuit, and records the change to notify.
+//
+// The caller must hold the lock.
+func (cb *CircuitBreaker) transition(from, to CircuitState) {
+	cb.state = to
+	cb.generation++
+	if cb.config.OnStateChange != nil {
+		cb.changes = append(cb.changes, circuitStateChange{from: from, to: to})
+	}
+}
+
+// unlock releases the lock, then notifies the pending state changes.
+//
+// Notifying without the lock allows the callback to call the breaker.
+func (cb *CircuitBreaker) unlock() {
+	changes := cb.changes
+	cb.changes = nil
+	cb.mu.Unlock()
+
+	for _, change := range changes {
+		cb.config.OnStateChange(cb.host, change.from, change.to)
+	}
+}
+
+// circuitBreakers holds a circuit breaker for each host called by a client.
+type circuitBreakers struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+func (c *circuitBreakers) forHost(host string) *CircuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cb, ok := c.breakers[host]
+	if !ok {
+		cb = NewCircuitBreaker(host, c.config)
+		c.breakers[host] = cb
+	}
+
+	return cb
+}
@@ -0,0 +1,271 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubescape/backend/pkg/server/v1/systemreports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stateRecorder records the state changes of circuit breakers.
+type stateRecorder struct {
+	mu      sync.Mutex
+	changes []CircuitState
+}
+
+func (r *stateRecorder) onStateChange(_ string, _, to CircuitState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.changes = append(r.changes, to)
+}
+
+func (r *stateRecorder) get() []CircuitState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]CircuitState(nil), r.changes...)
+}
+
+// failingHttpSender fails every report, unless healthy.
+type failingHttpSender struct {
+	healthy atomic.Bool
+	calls   atomic.Int32
+}
+
+func (s *failingHttpSender) Send(_ string, _ map[string]string, _ []byte) (int, string, error) {
+	s.calls.Add(1)
+	if s.healthy.Load() {
+		return http.StatusOK, "ok", nil
+	}
+
+	return http.StatusInternalServerError, "", errors.New("event receiver is down")
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should cycle through closed, open and half-open states", func(t *testing.T) {
+		t.Parallel()
+
+		var recorder stateRecorder
+		cb := NewCircuitBreaker("api.example.com", CircuitBreakerConfig{
+			FailureThreshold: 2,
+			CoolDown:         20 * time.Millisecond,
+			OnStateChange:    recorder.onStateChange,
+		})
+		failure := &http.Response{StatusCode: http.StatusBadGateway}
+		success := &http.Response{StatusCode: http.StatusOK}
+
+		require.Equal(t, CircuitClosed, cb.State())
+		require.Equal(t, "api.example.com", cb.Host())
+
+		for range 2 {
+			generation, err := cb.allow()
+			require.NoError(t, err)
+			cb.record(generation, failure, nil)
+		}
+		require.Equal(t, CircuitOpen, cb.State())
+		_, err := cb.allow()
+		require.ErrorIs(t, err, ErrCircuitOpen)
+
+		time.Sleep(30 * time.Millisecond)
+		require.Equal(t, CircuitHalfOpen, cb.State())
+
+		// a single probe is let through, which fails and opens the circuit again
+		generation, err := cb.allow()
+		require.NoError(t, err)
+		_, err = cb.allow()
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		cb.record(generation, nil, errors.New("connection refused"))
+		require.Equal(t, CircuitOpen, cb.State())
+
+		time.Sleep(30 * time.Millisecond)
+		generation, err = cb.allow()
+		require.NoError(t, err)
+		cb.record(generation, success, nil)
+		require.Equal(t, CircuitClosed, cb.State())
+
+		require.Equal(t,
+			[]CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed},
+			recorder.get(),
+		)
+	})
+
+	t.Run("should reset the failure count on success", func(t *testing.T) {
+		t.Parallel()
+
+		cb := NewCircuitBreaker("api.example.com", CircuitBreakerConfig{FailureThreshold: 2})
+		cb.record(0, nil, errors.New("connection refused"))
+		cb.record(0, &http.Response{StatusCode: http.StatusOK}, nil)
+		cb.record(0, nil, errors.New("connection refused"))
+		require.Equal(t, CircuitClosed, cb.State())
+
+		// client-side errors and cancellations do not count as failures of the host
+		cb.record(0, &http.Response{StatusCode: http.StatusNotFound}, nil)
+		cb.record(0, nil, context.Canceled)
+		require.Equal(t, CircuitClosed, cb.State())
+	})
+
+	t.Run("should let the state change callback call the breaker", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			cb     *CircuitBreaker
+			states []CircuitState
+		)
+		cb = NewCircuitBreaker("api.example.com", CircuitBreakerConfig{
+			FailureThreshold: 1,
+			CoolDown:         20 * time.Millisecond,
+			OnStateChange: func(_ string, _, _ CircuitState) {
+				states = append(states, cb.State())
+			},
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			generation, err := cb.allow()
+			if !assert.NoError(t, err) {
+				return
+			}
+			cb.record(generation, nil, errors.New("connection refused"))
+
+			time.Sleep(30 * time.Millisecond)
+			_ = cb.State()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "the circuit breaker deadlocked")
+		}
+
+		require.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen}, states)
+	})
+
+	t.Run("should ignore the outcome of requests allowed before the circuit changed state", func(t *testing.T) {
+		t.Parallel()
+
+		cb := NewCircuitBreaker("api.example.com", CircuitBreakerConfig{FailureThreshold: 1, CoolDown: 20 * time.Millisecond})
+
+		slow, err := cb.allow()
+		require.NoError(t, err)
+		failed, err := cb.allow()
+		require.NoError(t, err)
+		cb.record(failed, nil, errors.New("connection refused"))
+		require.Equal(t, CircuitOpen, cb.State())
+
+		// a slow request started before the circuit opened does not close it
+		cb.record(slow, &http.Response{StatusCode: http.StatusOK}, nil)
+		require.Equal(t, CircuitOpen, cb.State())
+
+		// nor does it count as a probe once half-open
+		time.Sleep(30 * time.Millisecond)
+		probe, err := cb.allow()
+		require.NoError(t, err)
+		cb.record(slow, &http.Response{StatusCode: http.StatusOK}, nil)
+		require.Equal(t, CircuitHalfOpen, cb.State())
+		_, err = cb.allow()
+		require.ErrorIs(t, err, ErrCircuitOpen)
+
+		cb.record(probe, &http.Response{StatusCode: http.StatusOK}, nil)
+		require.Equal(t, CircuitClosed, cb.State())
+	})
+
+	t.Run("should fail fast when the report host is down", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 100, http.StatusServiceUnavailable, nil)
+
+		var recorder stateRecorder
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "",
+			WithRetryPolicy(fastRetryPolicy()),
+			WithCircuitBreaker(CircuitBreakerConfig{
+				FailureThreshold: 2,
+				CoolDown:         time.Hour,
+				OnStateChange:    recorder.onStateChange,
+			}),
+		)
+		require.NoError(t, err)
+
+		_, err = ks.SubmitReport(mockPostureReport(t, "", ""))
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.Equal(t, int32(2), calls.Load())
+
+		_, err = ks.GetAttackTracks()
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.Equal(t, int32(2), calls.Load())
+		require.Equal(t, []CircuitState{CircuitOpen}, recorder.get())
+	})
+
+	t.Run("should protect the event receiver", func(t *testing.T) {
+		t.Parallel()
+
+		sender := &failingHttpSender{}
+		cb := NewCircuitBreaker("event-receiver", CircuitBreakerConfig{
+			FailureThreshold: 2,
+			CoolDown:         20 * time.Millisecond,
+		})
+
+		reporter := NewBaseReportSender("https://dummyeventreceiver.com", nil, map[string]string{},
+			systemreports.NewBaseReport("a-user-guid", "my-reporter"),
+		)
+		reporter.httpSender = sender
+		reporter.SetCircuitBreaker(cb)
+
+		for range 2 {
+			_, _, err := reporter.Send()
+			require.Error(t, err)
+			require.NotErrorIs(t, err, ErrCircuitOpen)
+		}
+
+		status, _, err := reporter.Send()
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Equal(t, int32(2), sender.calls.Load())
+
+		time.Sleep(30 * time.Millisecond)
+		sender.healthy.Store(true)
+
+		status, _, err = reporter.Send()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, CircuitClosed, cb.State())
+	})
+}
+
+// TestCircuitBreakerClosesRequestBody is not parallel, as it counts goroutines.
+func TestCircuitBreakerClosesRequestBody(t *testing.T) {
+	srv, _ := flakyServer(t, 100, http.StatusServiceUnavailable, nil)
+
+	ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "",
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, CoolDown: time.Hour}),
+	)
+	require.NoError(t, err)
+
+	report := mockPostureReport(t, "", "")
+	_, err = ks.SubmitReport(report)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+
+	before := runtime.NumGoroutine()
+	for range 50 {
+		_, err = ks.SubmitReport(report)
+		require.ErrorIs(t, err, ErrCircuitOpen)
+	}
+
+	// the goroutines streaming the rejected reports terminate once their body is closed
+	require.Eventually(t, func() bool {
+		return runtime.NumGoroutine() <= before+5
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,220 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	v1 "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/kubescape/backend/pkg/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+// controlIndex caches controls by control ID.
+type controlIndex struct {
+	mu       sync.Mutex
+	controls map[string]*Control
+	ids      []string
+
+	// generation is incremented by reset, so that an index built from a fetch started before is not cached
+	generation uint64
+
+	// loading shares a single fetch between concurrent callers
+	loading singleflight.Group
+
+	// noEndpoint is set once the backend is known not to expose a dedicated controls endpoint
+	noEndpoint atomic.Bool
+}
+
+// indexedControls is the result of a shared fetch.
+type indexedControls struct {
+	controls map[string]*Control
+	ids      []string
+}
+
+// load returns the cached index, or builds it when empty.
+//
+// Concurrent callers share a single fetch, which is not canceled by any one of them: each caller stops waiting
+// when its own context is done.
+func (idx *controlIndex) load(ctx context.Context, fetch func(context.Context) ([]Control, error)) (map[string]*Control, []string, error) {
+	idx.mu.Lock()
+	controls, ids, generation := idx.controls, idx.ids, idx.generation
+	idx.mu.Unlock()
+
+	if controls != nil {
+		return controls, ids, nil
+	}
+
+	ch := idx.loading.DoChan(strconv.FormatUint(generation, 10), func() (any, error) {
+		fetched, err := fetch(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		controls, ids := indexControls(fetched)
+
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+
+		if idx.generation == generation {
+			idx.controls, idx.ids = controls, ids
+		}
+
+		return indexedControls{controls: controls, ids: ids}, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, nil, res.Err
+		}
+
+		indexed := res.Val.(indexedControls)
+
+		return indexed.controls, indexed.ids, nil
+	}
+}
+
+// reset clears the cached index, so it is rebuilt on the next call.
+func (idx *controlIndex) reset() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.controls = nil
+	idx.ids = nil
+	idx.generation++
+}
+
+// indexControls deduplicates controls by ID, and returns the sorted list of IDs.
+//
+// When a control appears several times, the first occurrence is retained and framework names are merged.
+func indexControls(controls []Control) (map[string]*Control, []string) {
+	index := make(map[string]*Control, len(controls))
+	ids := make([]string, 0, len(controls))
+
+	for i := range controls {
+		control := controls[i]
+		if control.ControlID == "" {
+			continue
+		}
+
+		known, ok := index[control.ControlID]
+		if !ok {
+			index[control.ControlID] = &control
+			ids = append(ids, control.ControlID)
+
+			continue
+		}
+
+		for _, name := range control.FrameworkNames {
+			if !slices.Contains(known.FrameworkNames, name) {
+				known.FrameworkNames = append(known.FrameworkNames, name)
+			}
+		}
+	}
+
+	sort.Strings(ids)
+
+	return index, ids
+}
+
+// cloneControl returns a copy of an indexed control, which callers may alter without corrupting the index.
+func cloneControl(control *Control) *Control {
+	clone := *control
+	clone.FrameworkNames = slices.Clone(control.FrameworkNames)
+
+	return &clone
+}
+
+// controlsFromFrameworks collects the controls of all frameworks, recording which frameworks hold them.
+func controlsFromFrameworks(frameworks []Framework) []Control {
+	controls := make([]Control, 0, len(frameworks))
+	for _, framework := range frameworks {
+		for _, control := range framework.Controls {
+			if !slices.Contains(control.FrameworkNames, framework.Name) {
+				control.FrameworkNames = append(slices.Clone(control.FrameworkNames), framework.Name)
+			}
+
+			controls = append(controls, control)
+		}
+	}
+
+	return controls
+}
+
+// fetchControls retrieves all controls from the dedicated endpoint, if the backend exposes one,
+// or else derives them from all registered frameworks.
+func (api *KSCloudAPI) fetchControls(ctx context.Context) ([]Control, error) {
+	if !api.controls.noEndpoint.Load() {
+		controls, supported, err := api.getControlsFromEndpoint(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if supported {
+			return controls, nil
+		}
+	}
+
+	frameworks, err := api.GetFrameworksCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return controlsFromFrameworks(frameworks), nil
+}
+
+// getControlsFromEndpoint retrieves all controls from the dedicated endpoint.
+//
+// It returns false whenever the endpoint does not respond with controls, so that they are derived from frameworks.
+// A backend which does not expose this endpoint is remembered, and the endpoint is not probed again.
+func (api *KSCloudAPI) getControlsFromEndpoint(ctx context.Context) ([]Control, bool, error) {
+	settings := api.current()
+	o := api.defaultRequestOptions(ctx, []RequestOption{withSettings(settings)})
+	req, err := http.NewRequestWithContext(o.reqContext, http.MethodGet, api.getControlsURL(settings), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	o.setHeaders(req)
+
+	resp, err := api.doWithRetry(req, o)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		switch resp.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			api.controls.noEndpoint.Store(true)
+		}
+
+		return nil, false, nil
+	}
+
+	controls, err := utils.Decode[[]Control](resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return controls, true, nil
+}
+
+func (api *KSCloudAPI) getControlsURL(settings ksCloudSettings) string {
+	return settings.buildAPIURL(
+		v1.ApiServerControlsPath,
+		append(
+			settings.paramsWithGUID(),
+			v1.QueryParamGitRegoStoreVersion, v1.RegolibraryVersion,
+		)...,
+	)
+}
@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/armosec/armoapi-go/armotypes"
+	backendServer "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexControls(t *testing.T) {
+	t.Parallel()
+
+	frameworks := []Framework{
+		{PortalBase: armotypes.PortalBase{Name: "fw-1"}, Controls: []Control{mockControl("C-2"), mockControl("C-1")}},
+		{PortalBase: armotypes.PortalBase{Name: "fw-2"}, Controls: []Control{mockControl("C-1"), mockControl("")}},
+	}
+
+	index, ids := indexControls(controlsFromFrameworks(frameworks))
+	require.Equal(t, []string{"C-1", "C-2"}, ids)
+	require.Len(t, index, 2)
+	require.Equal(t, []string{"fw-1", "fw-2"}, index["C-1"].FrameworkNames)
+	require.Equal(t, []string{"fw-1"}, index["C-2"].FrameworkNames)
+}
+
+func TestControlsEndpoint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should use the dedicated endpoint when available, and cache the index", func(t *testing.T) {
+		t.Parallel()
+
+		var controlCalls, frameworkCalls atomic.Int32
+		h := http.NewServeMux()
+		h.HandleFunc(backendServer.ApiServerControlsPath, func(w http.ResponseWriter, _ *http.Request) {
+			controlCalls.Add(1)
+			_ = json.NewEncoder(w).Encode([]Control{mockControl("C-0001"), mockControl("C-0002")})
+		})
+		h.HandleFunc(backendServer.ApiServerFrameworksPath, func(w http.ResponseWriter, _ *http.Request) {
+			frameworkCalls.Add(1)
+			_ = json.NewEncoder(w).Encode(mockFrameworks())
+		})
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "")
+		require.NoError(t, err)
+
+		control, err := ks.GetControl("C-0002")
+		require.NoError(t, err)
+		require.Equal(t, "C-0002", control.ControlID)
+
+		ids, err := ks.ListControls()
+		require.NoError(t, err)
+		require.Equal(t, []string{"C-0001", "C-0002"}, ids)
+
+		require.Equal(t, int32(1), controlCalls.Load())
+		require.Zero(t, frameworkCalls.Load())
+	})
+
+	t.Run("should fall back to frameworks when the endpoint is missing", func(t *testing.T) {
+		t.Parallel()
+
+		srv := MockAPIServer(t)
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.Root(), srv.Root(), "account", "")
+		require.NoError(t, err)
+
+		control, err := ks.GetControl("control-6")
+		require.NoError(t, err)
+		require.Equal(t, []string{"nsa"}, control.FrameworkNames)
+		require.True(t, ks.controls.noEndpoint.Load())
+
+		ks.controls.reset()
+		_, err = ks.ListControls()
+		require.NoError(t, err)
+	})
+
+	t.Run("should fall back to frameworks when the endpoint fails, and probe it again", func(t *testing.T) {
+		t.Parallel()
+
+		var controlCalls atomic.Int32
+		h := http.NewServeMux()
+		h.HandleFunc(backendServer.ApiServerControlsPath, func(w http.ResponseWriter, _ *http.Request) {
+			controlCalls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		h.HandleFunc(backendServer.ApiServerFrameworksPath, func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode([]Framework{
+				{PortalBase: armotypes.PortalBase{Name: "fw-1"}, Controls: []Control{mockControl("C-0001")}},
+			})
+		})
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "")
+		require.NoError(t, err)
+
+		control, err := ks.GetControl("C-0001")
+		require.NoError(t, err)
+		require.Equal(t, []string{"fw-1"}, control.FrameworkNames)
+		require.False(t, ks.controls.noEndpoint.Load())
+
+		ks.controls.reset()
+		_, err = ks.ListControls()
+		require.NoError(t, err)
+		require.Equal(t, int32(2), controlCalls.Load())
+	})
+
+	t.Run("should not let callers alter the cached index", func(t *testing.T) {
+		t.Parallel()
+
+		srv := MockAPIServer(t)
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.Root(), srv.Root(), "account", "")
+		require.NoError(t, err)
+
+		control, err := ks.GetControl("control-6")
+		require.NoError(t, err)
+		control.Name = "altered"
+		control.FrameworkNames[0] = "altered"
+
+		ids, err := ks.ListControls()
+		require.NoError(t, err)
+		first := ids[0]
+		ids[0] = "altered"
+
+		control, err = ks.GetControl("control-6")
+		require.NoError(t, err)
+		require.NotEqual(t, "altered", control.Name)
+		require.Equal(t, []string{"nsa"}, control.FrameworkNames)
+
+		ids, err = ks.ListControls()
+		require.NoError(t, err)
+		require.Equal(t, first, ids[0])
+	})
+
+	t.Run("should report API errors", func(t *testing.T) {
+		t.Parallel()
+
+		errAPI := errors.New("test error")
+		srv := MockAPIServer(t, withAPIError(errAPI))
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.Root(), srv.Root(), "account", "")
+		require.NoError(t, err)
+
+		_, err = ks.GetControl("control-6")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errAPI.Error())
+	})
+	t.Run("should share a single fetch between concurrent callers", func(t *testing.T) {
+		t.Parallel()
+
+		var controlCalls atomic.Int32
+		release := make(chan struct{})
+		h := http.NewServeMux()
+		h.HandleFunc(backendServer.ApiServerControlsPath, func(w http.ResponseWriter, _ *http.Request) {
+			controlCalls.Add(1)
+			<-release
+			_ = json.NewEncoder(w).Encode([]Control{mockControl("C-0001")})
+		})
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "")
+		require.NoError(t, err)
+
+		// a caller giving up does not cancel the fetch shared with the others
+		ctx, cancel := context.WithCancel(context.Background())
+		canceled := make(chan error)
+		go func() {
+			_, err := ks.GetControlCtx(ctx, "C-0001")
+			canceled <- err
+		}()
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				control, err := ks.GetControl("C-0001")
+				if assert.NoError(t, err) {
+					assert.Equal(t, "C-0001", control.ControlID)
+				}
+			}()
+		}
+
+		require.Eventually(t, func() bool { return controlCalls.Load() == 1 }, 5*time.Second, time.Millisecond)
+		cancel()
+		require.ErrorIs(t, <-canceled, context.Canceled)
+
+		close(release)
+		wg.Wait()
+		require.Equal(t, int32(1), controlCalls.Load())
+
+		// the index is rebuilt for another account
+		ks.SetAccountID("other-account")
+		_, err = ks.ListControls()
+		require.NoError(t, err)
+		require.Equal(t, int32(2), controlCalls.Load())
+	})
+}
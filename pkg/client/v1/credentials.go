@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/kubescape/backend/pkg/utils"
+)
+
+// WatchCredentials keeps the credentials of clients, such as KSCloudAPI or StorageClient,
+// up to date with a mounted secret directory, until the context is cancelled.
+//
+// Whenever Kubernetes rotates the secret, the new access key and account are set on all clients.
+// Values missing from the secret are left unchanged. The returned watcher may be used to be notified as well.
+//
+// A KSCloudAPI configured with an authenticator only sends the rotated access key if the authenticator
+// accepts one (see WithAuthenticator).
+func WatchCredentials(ctx context.Context, secretPath string, clients ...ICredentialsReceiver) (*utils.CredentialsWatcher, error) {
+	watcher, err := utils.NewCredentialsWatcher(secretPath)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher.OnUpdate(func(credentials utils.Credentials) {
+		for _, client := range clients {
+			if credentials.AccessKey != "" {
+				client.SetAccessKey(credentials.AccessKey)
+			}
+			if credentials.Account != "" {
+				client.SetAccountID(credentials.Account)
+			}
+		}
+	})
+
+	if err := watcher.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	return watcher, nil
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubescape/backend/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchCredentials(t *testing.T) {
+	t.Parallel()
+
+	secretPath := t.TempDir()
+	writeSecret := func(accessKey string) {
+		pth := filepath.Join(secretPath, utils.AccessKeySecretKey)
+		require.NoError(t, os.WriteFile(pth+".tmp", []byte(accessKey+"\n"), 0o600))
+		require.NoError(t, os.Rename(pth+".tmp", pth))
+	}
+	writeSecret("key-1")
+
+	ks, err := NewKSCloudAPI("api.example.com", "report.example.com", "account", "key-1")
+	require.NoError(t, err)
+
+	storage, err := NewStorageClient("grpc://localhost:50051", "account", "key-1", "cluster")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	watcher, err := WatchCredentials(ctx, secretPath, ks, storage)
+	require.NoError(t, err)
+
+	writeSecret("key-2")
+
+	select {
+	case update := <-watcher.Updates():
+		require.Equal(t, "key-2", update.AccessKey)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "expected a credentials update")
+	}
+
+	require.Equal(t, "key-2", ks.GetAccessKey())
+	require.Equal(t, "key-2", storage.GetAccessKey())
+
+	// values missing from the secret are retained
+	require.Equal(t, "account", ks.GetAccountID())
+	require.Equal(t, "account", storage.GetAccountID())
+}
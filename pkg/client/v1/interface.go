@@ -0,0 +1,47 @@
+package v1
+
+var (
+	_ IKSCloudAPI = &KSCloudAPI{}
+	_ IKSCloudAPI = &LocalKSCloudAPI{}
+
+	_ IPolicyGetter = &KSCloudBundle{}
+
+	_ ICredentialsReceiver = &KSCloudAPI{}
+	_ ICredentialsReceiver = &StorageClient{}
+)
+
+type (
+	// IPolicyGetter knows how to retrieve policies: frameworks, controls, attack tracks,
+	// exceptions and the account configuration.
+	IPolicyGetter interface {
+		GetAttackTracks() ([]AttackTrack, error)
+		GetFramework(frameworkName string) (*Framework, error)
+		GetFrameworks() ([]Framework, error)
+		ListCustomFrameworks() ([]string, error)
+		ListFrameworks() ([]string, error)
+		GetControl(ID string) (*Control, error)
+		ListControls() ([]string, error)
+		GetExceptions(clusterName string) ([]PostureExceptionPolicy, error)
+		GetAccountConfig(clusterName string) (*CustomerConfig, error)
+		GetControlsInputs(clusterName string) (map[string][]string, error)
+	}
+
+	// IReportSubmitter knows how to submit posture reports.
+	IReportSubmitter interface {
+		SubmitReport(report *PostureReport) (string, error)
+	}
+
+	// IKSCloudAPI is the interface of a Kubescape Cloud client.
+	//
+	// Implementations are KSCloudAPI (remote API) and LocalKSCloudAPI (local directory tree).
+	IKSCloudAPI interface {
+		IPolicyGetter
+		IReportSubmitter
+	}
+
+	// ICredentialsReceiver is a client whose credentials may be rotated, e.g. by WatchCredentials.
+	ICredentialsReceiver interface {
+		SetAccountID(value string)
+		SetAccessKey(value string)
+	}
+)
@@ -2,23 +2,60 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	v1 "github.com/kubescape/backend/pkg/server/v1"
 	"github.com/kubescape/backend/pkg/utils"
 )
 
 var (
+	// ErrAPINotPublic is no longer returned: controls are now served from frameworks or a dedicated endpoint.
+	//
+	// Deprecated: kept for backward compatibility.
 	ErrAPINotPublic = errors.New("control api is not public")
+
+	ErrFrameworkNotFound = errors.New("framework not found")
+	ErrControlNotFound   = errors.New("control not found")
+	ErrExceptionName     = errors.New("exception policy name is required")
+	ErrFrameworkName     = errors.New("framework name is required")
+	ErrNativeFramework   = errors.New("native frameworks cannot be altered")
+	ErrUnknownControl    = errors.New("framework references an unknown control")
+
+	// Errors returned by the backend, to be matched with errors.Is.
+	ErrUnauthorized = utils.ErrUnauthorized
+	ErrNotFound     = utils.ErrNotFound
+	ErrRateLimited  = utils.ErrRateLimited
 )
 
+// APIError is an error response from the backend.
+//
+// Failed API calls return an *APIError, which may be inspected with errors.As.
+type APIError = utils.APIError
+
 // KSCloudAPI allows to access the API of the Kubescape Cloud offering.
+//
+// Credentials and URLs may be updated while requests are in flight.
 type KSCloudAPI struct {
 	*KsCloudOptions
+	mu       sync.Mutex // serializes updates of the settings
+	settings atomic.Pointer[ksCloudSettings]
+	controls controlIndex
+}
+
+// ksCloudSettings holds the credentials and endpoints of a KSCloudAPI.
+//
+// Settings are never mutated: updates swap in a new copy. A request takes a single snapshot, which its URL
+// and its credentials are built from (see withSettings).
+type ksCloudSettings struct {
 	accountID    string
 	accessKey    string
 	apiHost      string
@@ -39,9 +76,11 @@ func NewEmptyKSCloudAPI(opts ...KSCloudOption) *KSCloudAPI {
 func NewKSCloudAPI(apiURL, reportURL, accountID, accessKey string, opts ...KSCloudOption) (*KSCloudAPI, error) {
 	api := &KSCloudAPI{
 		KsCloudOptions: ksCloudOptionsWithDefaults(opts),
-		accountID:      accountID,
-		accessKey:      accessKey,
 	}
+	api.settings.Store(&ksCloudSettings{
+		accountID: accountID,
+		accessKey: accessKey,
+	})
 
 	if err := api.SetCloudAPIURL(apiURL); err != nil {
 		return nil, err
@@ -54,40 +93,103 @@ func NewKSCloudAPI(apiURL, reportURL, accountID, accessKey string, opts ...KSClo
 	return api, nil
 }
 
+// current yields a snapshot of the current settings.
+func (api *KSCloudAPI) current() ksCloudSettings {
+	if settings := api.settings.Load(); settings != nil {
+		return *settings
+	}
+
+	return ksCloudSettings{}
+}
+
+// updateSettings applies a change to a copy of the current settings, then swaps it in.
+func (api *KSCloudAPI) updateSettings(update func(*ksCloudSettings)) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	settings := api.current()
+	update(&settings)
+	api.settings.Store(&settings)
+}
+
+// SetAccountID sets the customer account's GUID.
+//
+// The cached index of controls is cleared, as controls may differ between accounts.
 func (api *KSCloudAPI) SetAccountID(value string) {
-	api.accountID = value
+	api.updateSettings(func(settings *ksCloudSettings) {
+		settings.accountID = value
+	})
+	api.controls.reset()
 }
 
+// SetAccessKey sets the API access key.
+//
+// When the client is configured with an authenticator (see WithAuthenticator), the key is forwarded to it
+// if it accepts one, like StaticKeyAuthenticator. Other authenticators, such as TokenFileAuthenticator or
+// OAuth2Authenticator, hold their own credentials and ignore the access key.
 func (api *KSCloudAPI) SetAccessKey(value string) {
-	api.accessKey = value
+	api.updateSettings(func(settings *ksCloudSettings) {
+		settings.accessKey = value
+	})
+
+	if receiver, ok := api.authenticator.(interface{ SetAccessKey(string) }); ok {
+		receiver.SetAccessKey(value)
+	}
 }
 
 // GetAccountID returns the customer account's GUID.
-func (api *KSCloudAPI) GetAccountID() string { return api.accountID }
+func (api *KSCloudAPI) GetAccountID() string { return api.current().accountID }
+
+func (api *KSCloudAPI) GetAccessKey() string { return api.current().accessKey }
+
+// authenticate decorates a request with credentials, using the configured authenticator or else the access key
+// of the settings the request was built with.
+func (api *KSCloudAPI) authenticate(req *http.Request, settings ksCloudSettings) error {
+	if api.authenticator != nil {
+		return api.authenticator.Authenticate(req)
+	}
+
+	if accessKey := settings.accessKey; accessKey != "" {
+		req.Header.Set(v1.AccessKeyHeader, accessKey)
+	}
 
-func (api *KSCloudAPI) GetAccessKey() string { return api.accessKey }
+	return nil
+}
 
 func (api *KSCloudAPI) GetCloudReportURL() string {
-	if api.reportHost == "" {
+	settings := api.current()
+	if settings.reportHost == "" {
 		return ""
 	}
 
-	return api.reportScheme + "://" + api.reportHost
+	return settings.reportScheme + "://" + settings.reportHost
 }
 
 func (api *KSCloudAPI) GetCloudAPIURL() string {
-	if api.apiHost == "" {
+	settings := api.current()
+	if settings.apiHost == "" {
 		return ""
 	}
-	return api.apiScheme + "://" + api.apiHost
+	return settings.apiScheme + "://" + settings.apiHost
 }
 
 func (api *KSCloudAPI) SetCloudAPIURL(cloudAPIURL string) (err error) {
 	if cloudAPIURL == "" {
 		return nil
 	}
-	api.apiScheme, api.apiHost, err = utils.ParseHost(cloudAPIURL)
-	return err
+
+	api.updateSettings(func(settings *ksCloudSettings) {
+		settings.apiScheme, settings.apiHost, err = utils.ParseHost(cloudAPIURL)
+	})
+	if err != nil {
+		return err
+	}
+
+	// the new backend may expose a different set of controls, or a dedicated endpoint for them
+	api.controls.noEndpoint.Store(false)
+	api.controls.reset()
+
+	return nil
 }
 
 func (api *KSCloudAPI) SetCloudReportURL(cloudReportURL string) (err error) {
@@ -95,12 +197,21 @@ func (api *KSCloudAPI) SetCloudReportURL(cloudReportURL string) (err error) {
 		return nil
 	}
 
-	api.reportScheme, api.reportHost, err = utils.ParseHost(cloudReportURL)
+	api.updateSettings(func(settings *ksCloudSettings) {
+		settings.reportScheme, settings.reportHost, err = utils.ParseHost(cloudReportURL)
+	})
 	return err
 }
 
+// GetAttackTracks retrieves all attack tracks.
 func (api *KSCloudAPI) GetAttackTracks() ([]AttackTrack, error) {
-	rdr, _, err := api.get(api.getAttackTracksURL())
+	return api.GetAttackTracksCtx(context.Background())
+}
+
+// GetAttackTracksCtx retrieves all attack tracks, with a context.
+func (api *KSCloudAPI) GetAttackTracksCtx(ctx context.Context) ([]AttackTrack, error) {
+	settings := api.current()
+	rdr, _, err := api.getCached(ctx, api.getAttackTracksURL(settings), withSettings(settings))
 	if err != nil {
 		return nil, err
 	}
@@ -114,11 +225,11 @@ func (api *KSCloudAPI) GetAttackTracks() ([]AttackTrack, error) {
 	return attackTracks, nil
 }
 
-func (api *KSCloudAPI) getAttackTracksURL() string {
-	return api.buildAPIURL(
+func (api *KSCloudAPI) getAttackTracksURL(settings ksCloudSettings) string {
+	return settings.buildAPIURL(
 		v1.ApiServerAttackTracksPath,
 		append(
-			api.paramsWithGUID(),
+			settings.paramsWithGUID(),
 			v1.QueryParamGitRegoStoreVersion, v1.RegolibraryVersion,
 		)...,
 	)
@@ -126,7 +237,13 @@ func (api *KSCloudAPI) getAttackTracksURL() string {
 
 // GetFramework retrieves a framework by name.
 func (api *KSCloudAPI) GetFramework(frameworkName string) (*Framework, error) {
-	rdr, _, err := api.get(api.getFrameworkURL(frameworkName))
+	return api.GetFrameworkCtx(context.Background(), frameworkName)
+}
+
+// GetFrameworkCtx retrieves a framework by name, with a context.
+func (api *KSCloudAPI) GetFrameworkCtx(ctx context.Context, frameworkName string) (*Framework, error) {
+	settings := api.current()
+	rdr, _, err := api.getCached(ctx, api.getFrameworkURL(settings, frameworkName), withSettings(settings))
 	if err != nil {
 		return nil, err
 	}
@@ -140,16 +257,16 @@ func (api *KSCloudAPI) GetFramework(frameworkName string) (*Framework, error) {
 	return &framework, err
 }
 
-func (api *KSCloudAPI) getFrameworkURL(frameworkName string) string {
+func (api *KSCloudAPI) getFrameworkURL(settings ksCloudSettings, frameworkName string) string {
 	if utils.IsNativeFramework(frameworkName) {
 		// Native framework name is normalized as upper case, but for a custom framework the name remains unaltered
 		frameworkName = strings.ToUpper(frameworkName)
 	}
 
-	return api.buildAPIURL(
+	return settings.buildAPIURL(
 		v1.ApiServerFrameworksPath,
 		append(
-			api.paramsWithGUID(),
+			settings.paramsWithGUID(),
 			v1.QueryParamFrameworkName, frameworkName,
 			v1.QueryParamGitRegoStoreVersion, v1.RegolibraryVersion,
 		)...,
@@ -158,7 +275,13 @@ func (api *KSCloudAPI) getFrameworkURL(frameworkName string) string {
 
 // GetFrameworks returns all registered frameworks.
 func (api *KSCloudAPI) GetFrameworks() ([]Framework, error) {
-	rdr, _, err := api.get(api.getListFrameworkURL())
+	return api.GetFrameworksCtx(context.Background())
+}
+
+// GetFrameworksCtx returns all registered frameworks, with a context.
+func (api *KSCloudAPI) GetFrameworksCtx(ctx context.Context) ([]Framework, error) {
+	settings := api.current()
+	rdr, _, err := api.getCached(ctx, api.getListFrameworkURL(settings), withSettings(settings))
 	if err != nil {
 		return nil, err
 	}
@@ -172,11 +295,11 @@ func (api *KSCloudAPI) GetFrameworks() ([]Framework, error) {
 	return frameworks, err
 }
 
-func (api *KSCloudAPI) getListFrameworkURL() string {
-	return api.buildAPIURL(
+func (api *KSCloudAPI) getListFrameworkURL(settings ksCloudSettings) string {
+	return settings.buildAPIURL(
 		v1.ApiServerFrameworksPath,
 		append(
-			api.paramsWithGUID(),
+			settings.paramsWithGUID(),
 			v1.QueryParamGitRegoStoreVersion, v1.RegolibraryVersion,
 		)...,
 	)
@@ -184,7 +307,12 @@ func (api *KSCloudAPI) getListFrameworkURL() string {
 
 // ListCustomFrameworks lists the names of all non-native frameworks that have been registered for this account.
 func (api *KSCloudAPI) ListCustomFrameworks() ([]string, error) {
-	frameworks, err := api.GetFrameworks()
+	return api.ListCustomFrameworksCtx(context.Background())
+}
+
+// ListCustomFrameworksCtx lists the names of all non-native frameworks, with a context.
+func (api *KSCloudAPI) ListCustomFrameworksCtx(ctx context.Context) ([]string, error) {
+	frameworks, err := api.GetFrameworksCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +331,12 @@ func (api *KSCloudAPI) ListCustomFrameworks() ([]string, error) {
 
 // ListFrameworks list the names of all registered frameworks.
 func (api *KSCloudAPI) ListFrameworks() ([]string, error) {
-	frameworks, err := api.GetFrameworks()
+	return api.ListFrameworksCtx(context.Background())
+}
+
+// ListFrameworksCtx list the names of all registered frameworks, with a context.
+func (api *KSCloudAPI) ListFrameworksCtx(ctx context.Context) ([]string, error) {
+	frameworks, err := api.GetFrameworksCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -221,9 +354,165 @@ func (api *KSCloudAPI) ListFrameworks() ([]string, error) {
 	return frameworkList, nil
 }
 
+// CreateFramework creates a new custom framework.
+//
+// The framework is validated first: its name must not be the name of a native framework,
+// and all its controls must be known controls.
+func (api *KSCloudAPI) CreateFramework(framework *Framework) (*Framework, error) {
+	return api.CreateFrameworkCtx(context.Background(), framework)
+}
+
+// CreateFrameworkCtx creates a new custom framework, with a context.
+func (api *KSCloudAPI) CreateFrameworkCtx(ctx context.Context, framework *Framework) (*Framework, error) {
+	if err := api.validateFramework(ctx, framework); err != nil {
+		return nil, err
+	}
+
+	jazon, err := json.Marshal(framework)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := api.current()
+	rdr, _, err := api.post(ctx, api.frameworksURL(settings), jazon, withSettings(settings))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	defer api.controls.reset()
+
+	created, err := utils.Decode[Framework](rdr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateFramework updates an existing custom framework, identified by its name.
+//
+// The framework is validated first, like with CreateFramework.
+func (api *KSCloudAPI) UpdateFramework(framework *Framework) (*Framework, error) {
+	return api.UpdateFrameworkCtx(context.Background(), framework)
+}
+
+// UpdateFrameworkCtx updates an existing custom framework, with a context.
+func (api *KSCloudAPI) UpdateFrameworkCtx(ctx context.Context, framework *Framework) (*Framework, error) {
+	if err := api.validateFramework(ctx, framework); err != nil {
+		return nil, err
+	}
+
+	jazon, err := json.Marshal(framework)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := api.current()
+	rdr, _, err := api.put(ctx, api.frameworksURL(settings), jazon, WithIdempotent(true), withSettings(settings))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	defer api.controls.reset()
+
+	updated, err := utils.Decode[Framework](rdr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteFramework deletes a custom framework by name.
+func (api *KSCloudAPI) DeleteFramework(frameworkName string) error {
+	return api.DeleteFrameworkCtx(context.Background(), frameworkName)
+}
+
+// DeleteFrameworkCtx deletes a custom framework by name, with a context.
+func (api *KSCloudAPI) DeleteFrameworkCtx(ctx context.Context, frameworkName string) error {
+	if frameworkName == "" {
+		return ErrFrameworkName
+	}
+
+	if utils.IsNativeFramework(frameworkName) {
+		return fmt.Errorf("%w: %s", ErrNativeFramework, frameworkName)
+	}
+
+	settings := api.current()
+	rdr, _, err := api.delete(ctx, api.deleteFrameworkURL(settings, frameworkName), WithIdempotent(true), withSettings(settings))
+	if err != nil {
+		return err
+	}
+	defer api.controls.reset()
+
+	return rdr.Close()
+}
+
+// validateFramework checks a custom framework before it is uploaded.
+func (api *KSCloudAPI) validateFramework(ctx context.Context, framework *Framework) error {
+	if framework == nil || framework.Name == "" {
+		return ErrFrameworkName
+	}
+
+	if utils.IsNativeFramework(framework.Name) {
+		return fmt.Errorf("%w: %s", ErrNativeFramework, framework.Name)
+	}
+
+	controls, _, err := api.controls.load(ctx, api.fetchControls)
+	if err != nil {
+		return err
+	}
+
+	referenced := make([]string, 0, len(framework.Controls))
+	for _, control := range framework.Controls {
+		referenced = append(referenced, control.ControlID)
+	}
+
+	if framework.ControlsIDs != nil {
+		referenced = append(referenced, *framework.ControlsIDs...)
+	}
+
+	for _, subSection := range framework.SubSections {
+		if subSection != nil {
+			referenced = append(referenced, subSection.ControlIDs...)
+		}
+	}
+
+	for _, id := range referenced {
+		if _, ok := controls[id]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownControl, id)
+		}
+	}
+
+	return nil
+}
+
+func (api *KSCloudAPI) frameworksURL(settings ksCloudSettings) string {
+	return settings.buildAPIURL(
+		v1.ApiServerFrameworksPath,
+		settings.paramsWithGUID()...,
+	)
+}
+
+func (api *KSCloudAPI) deleteFrameworkURL(settings ksCloudSettings, frameworkName string) string {
+	return settings.buildAPIURL(
+		v1.ApiServerFrameworksPath,
+		append(
+			settings.paramsWithGUID(),
+			v1.QueryParamFrameworkName, frameworkName,
+		)...,
+	)
+}
+
 // GetExceptions returns exception policies.
 func (api *KSCloudAPI) GetExceptions(clusterName string) ([]PostureExceptionPolicy, error) {
-	rdr, _, err := api.get(api.getExceptionsURL(clusterName))
+	return api.GetExceptionsCtx(context.Background(), clusterName)
+}
+
+// GetExceptionsCtx returns exception policies, with a context.
+func (api *KSCloudAPI) GetExceptionsCtx(ctx context.Context, clusterName string) ([]PostureExceptionPolicy, error) {
+	settings := api.current()
+	rdr, _, err := api.getCached(ctx, api.getExceptionsURL(settings, clusterName), withSettings(settings))
 	if err != nil {
 		return nil, err
 	}
@@ -237,24 +526,129 @@ func (api *KSCloudAPI) GetExceptions(clusterName string) ([]PostureExceptionPoli
 	return exceptions, nil
 }
 
-func (api *KSCloudAPI) getExceptionsURL(clusterName string) string {
-	return api.buildAPIURL(
+func (api *KSCloudAPI) getExceptionsURL(settings ksCloudSettings, clusterName string) string {
+	return settings.buildAPIURL(
 		v1.ApiServerExceptionsPath,
 		append(
-			api.paramsWithGUID(),
+			settings.paramsWithGUID(),
 			v1.QueryParamGitRegoStoreVersion, v1.RegolibraryVersion,
 		)...,
 	)
 	// queryParamClusterName, clusterName, // TODO - fix customer name support in Armo BE
 }
 
+// CreateException creates a new posture exception policy.
+func (api *KSCloudAPI) CreateException(exception *PostureExceptionPolicy) (*PostureExceptionPolicy, error) {
+	return api.CreateExceptionCtx(context.Background(), exception)
+}
+
+// CreateExceptionCtx creates a new posture exception policy, with a context.
+func (api *KSCloudAPI) CreateExceptionCtx(ctx context.Context, exception *PostureExceptionPolicy) (*PostureExceptionPolicy, error) {
+	if exception == nil || exception.Name == "" {
+		return nil, ErrExceptionName
+	}
+
+	jazon, err := json.Marshal(exception)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := api.current()
+	rdr, _, err := api.post(ctx, api.exceptionsURL(settings), jazon, withSettings(settings))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	created, err := utils.Decode[PostureExceptionPolicy](rdr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateException updates an existing posture exception policy, identified by its name.
+func (api *KSCloudAPI) UpdateException(exception *PostureExceptionPolicy) (*PostureExceptionPolicy, error) {
+	return api.UpdateExceptionCtx(context.Background(), exception)
+}
+
+// UpdateExceptionCtx updates an existing posture exception policy, with a context.
+func (api *KSCloudAPI) UpdateExceptionCtx(ctx context.Context, exception *PostureExceptionPolicy) (*PostureExceptionPolicy, error) {
+	if exception == nil || exception.Name == "" {
+		return nil, ErrExceptionName
+	}
+
+	jazon, err := json.Marshal(exception)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := api.current()
+	rdr, _, err := api.put(ctx, api.exceptionsURL(settings), jazon, WithIdempotent(true), withSettings(settings))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	updated, err := utils.Decode[PostureExceptionPolicy](rdr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteException deletes a posture exception policy by name.
+func (api *KSCloudAPI) DeleteException(exceptionName string) error {
+	return api.DeleteExceptionCtx(context.Background(), exceptionName)
+}
+
+// DeleteExceptionCtx deletes a posture exception policy by name, with a context.
+func (api *KSCloudAPI) DeleteExceptionCtx(ctx context.Context, exceptionName string) error {
+	if exceptionName == "" {
+		return ErrExceptionName
+	}
+
+	settings := api.current()
+	rdr, _, err := api.delete(ctx, api.deleteExceptionURL(settings, exceptionName), WithIdempotent(true), withSettings(settings))
+	if err != nil {
+		return err
+	}
+
+	return rdr.Close()
+}
+
+func (api *KSCloudAPI) exceptionsURL(settings ksCloudSettings) string {
+	return settings.buildAPIURL(
+		v1.ApiServerExceptionsPath,
+		settings.paramsWithGUID()...,
+	)
+}
+
+func (api *KSCloudAPI) deleteExceptionURL(settings ksCloudSettings, exceptionName string) string {
+	return settings.buildAPIURL(
+		v1.ApiServerExceptionsPath,
+		append(
+			settings.paramsWithGUID(),
+			v1.QueryParamPolicyName, exceptionName,
+		)...,
+	)
+}
+
 // GetAccountConfig yields the account configuration.
 func (api *KSCloudAPI) GetAccountConfig(clusterName string) (*CustomerConfig, error) {
-	if api.accountID == "" {
+	return api.GetAccountConfigCtx(context.Background(), clusterName)
+}
+
+// GetAccountConfigCtx yields the account configuration, with a context.
+func (api *KSCloudAPI) GetAccountConfigCtx(ctx context.Context, clusterName string) (*CustomerConfig, error) {
+	settings := api.current()
+	if settings.accountID == "" {
 		return &CustomerConfig{}, nil
 	}
 
-	rdr, _, err := api.get(api.getAccountConfig(clusterName))
+	rdr, _, err := api.get(ctx, api.getAccountConfig(settings, clusterName), withSettings(settings))
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +657,7 @@ func (api *KSCloudAPI) GetAccountConfig(clusterName string) (*CustomerConfig, er
 	accountConfig, err := utils.Decode[CustomerConfig](rdr)
 	if err != nil {
 		// retry with default scope
-		rdr, _, err = api.get(api.getAccountConfigDefault(clusterName))
+		rdr, _, err = api.get(ctx, api.getAccountConfigDefault(settings, clusterName), withSettings(settings))
 		if err != nil {
 			return nil, err
 		}
@@ -278,14 +672,14 @@ func (api *KSCloudAPI) GetAccountConfig(clusterName string) (*CustomerConfig, er
 	return &accountConfig, nil
 }
 
-func (api *KSCloudAPI) getAccountConfig(clusterName string) string {
-	params := api.paramsWithGUID()
+func (api *KSCloudAPI) getAccountConfig(settings ksCloudSettings, clusterName string) string {
+	params := settings.paramsWithGUID()
 
 	if clusterName != "" { // TODO - fix customer name support in Armo BE
 		params = append(params, v1.QueryParamClusterName, clusterName)
 	}
 
-	return api.buildAPIURL(
+	return settings.buildAPIURL(
 		v1.ApiServerCustomerConfigPath,
 		append(
 			params,
@@ -294,9 +688,9 @@ func (api *KSCloudAPI) getAccountConfig(clusterName string) string {
 	)
 }
 
-func (api *KSCloudAPI) getAccountConfigDefault(clusterName string) string {
+func (api *KSCloudAPI) getAccountConfigDefault(settings ksCloudSettings, clusterName string) string {
 	params := append(
-		api.paramsWithGUID(),
+		settings.paramsWithGUID(),
 		v1.QueryParamScope, "customer",
 	)
 
@@ -304,7 +698,7 @@ func (api *KSCloudAPI) getAccountConfigDefault(clusterName string) string {
 		params = append(params, v1.QueryParamClusterName, clusterName)
 	}
 
-	return api.buildAPIURL(
+	return settings.buildAPIURL(
 		v1.ApiServerCustomerConfigPath,
 		append(
 			params,
@@ -315,7 +709,12 @@ func (api *KSCloudAPI) getAccountConfigDefault(clusterName string) string {
 
 // GetControlsInputs returns the controls inputs configured in the account configuration.
 func (api *KSCloudAPI) GetControlsInputs(clusterName string) (map[string][]string, error) {
-	accountConfig, err := api.GetAccountConfig(clusterName)
+	return api.GetControlsInputsCtx(context.Background(), clusterName)
+}
+
+// GetControlsInputsCtx returns the controls inputs configured in the account configuration, with a context.
+func (api *KSCloudAPI) GetControlsInputsCtx(ctx context.Context, clusterName string) (map[string][]string, error) {
+	accountConfig, err := api.GetAccountConfigCtx(ctx, clusterName)
 	if err != nil {
 		return nil, err
 	}
@@ -323,40 +722,76 @@ func (api *KSCloudAPI) GetControlsInputs(clusterName string) (map[string][]strin
 	return accountConfig.Settings.PostureControlInputs, nil
 }
 
-// GetControl is currently not exposed as a public API endpoint.
+// GetControl retrieves a control by ID.
+//
+// Controls are retrieved from a dedicated endpoint whenever the backend exposes one,
+// or otherwise derived from all registered frameworks. The index of controls is cached.
 func (api *KSCloudAPI) GetControl(ID string) (*Control, error) {
-	return nil, ErrAPINotPublic
+	return api.GetControlCtx(context.Background(), ID)
 }
 
-// ListControls is currently not exposed as a public API endpoint.
+// GetControlCtx retrieves a control by ID, with a context.
+func (api *KSCloudAPI) GetControlCtx(ctx context.Context, ID string) (*Control, error) {
+	controls, _, err := api.controls.load(ctx, api.fetchControls)
+	if err != nil {
+		return nil, err
+	}
+
+	control, ok := controls[ID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrControlNotFound, ID)
+	}
+
+	return cloneControl(control), nil
+}
+
+// ListControls lists the IDs of all controls.
+//
+// See GetControl.
 func (api *KSCloudAPI) ListControls() ([]string, error) {
-	return nil, ErrAPINotPublic
+	return api.ListControlsCtx(context.Background())
 }
 
-// SubmitReport uploads a posture report.
-func (api *KSCloudAPI) SubmitReport(report *PostureReport) (string, error) {
-	jazon, err := json.Marshal(report)
+// ListControlsCtx lists the IDs of all controls, with a context.
+func (api *KSCloudAPI) ListControlsCtx(ctx context.Context) ([]string, error) {
+	_, ids, err := api.controls.load(ctx, api.fetchControls)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	rdr, _, err := api.post(api.postReportURL(report.ClusterName, report.ReportID), jazon, WithContentJSON(true))
-	if err != nil {
-		return "", err
-	}
-	defer rdr.Close()
+	return slices.Clone(ids), nil
+}
+
+// SubmitReport uploads a posture report.
+func (api *KSCloudAPI) SubmitReport(report *PostureReport) (string, error) {
+	return api.SubmitReportCtx(context.Background(), report)
+}
+
+// SubmitReportCtx uploads a posture report, with a context.
+//
+// Request options may override the compression and chunking configured for the client
+// (see WithRequestCompression and WithRequestChunking).
+func (api *KSCloudAPI) SubmitReportCtx(ctx context.Context, report *PostureReport, opts ...RequestOption) (string, error) {
+	opts = append([]RequestOption{
+		WithRequestCompression(api.reportCompression),
+		WithRequestChunking(api.reportChunkSize),
+	}, opts...)
 
-	b, err := io.ReadAll(rdr)
-	if err == nil {
-		return string(b), nil
+	// all chunks are sent with the same settings
+	settings := api.current()
+	opts = append(opts, withSettings(settings))
+
+	if o := requestOptionsWithDefaults(opts); o.chunkSize > 0 {
+		return api.submitReportInChunks(ctx, settings, report, o.chunkSize, opts...)
 	}
-	return "", err
+
+	return api.postReport(ctx, settings, report, report, opts...)
 }
 
-func (api *KSCloudAPI) postReportURL(cluster, reportID string) string {
-	return api.buildReportURL(v1.ReporterReportPath,
+func (api *KSCloudAPI) postReportURL(settings ksCloudSettings, cluster, reportID string) string {
+	return settings.buildReportURL(v1.ReporterReportPath,
 		append(
-			api.paramsWithGUID(),
+			settings.paramsWithGUID(),
 			v1.QueryParamContextName, cluster,
 			v1.QueryParamClusterName, cluster, // deprecated
 			v1.QueryParamReport, reportID,
@@ -365,26 +800,26 @@ func (api *KSCloudAPI) postReportURL(cluster, reportID string) string {
 }
 
 // defaultRequestOptions adds standard authentication headers to all requests
-func (api *KSCloudAPI) defaultRequestOptions(opts []RequestOption) *RequestOptions {
+func (api *KSCloudAPI) defaultRequestOptions(ctx context.Context, opts []RequestOption) *RequestOptions {
 	optionsWithDefaults := []RequestOption{
+		withContext(ctx),
 		withTrace(api.withTrace),
 		WithContentJSON(true),
 	}
 
-	if api.accessKey != "" {
-		optionsWithDefaults = append(optionsWithDefaults,
-			WithHeaders(map[string]string{
-				v1.AccessKeyHeader: api.accessKey,
-			}))
-	}
-
 	optionsWithDefaults = append(optionsWithDefaults, opts...)
 
-	return requestOptionsWithDefaults(optionsWithDefaults)
+	o := requestOptionsWithDefaults(optionsWithDefaults)
+	if o.settings == nil {
+		settings := api.current()
+		o.settings = &settings
+	}
+
+	return o
 }
 
-func (api *KSCloudAPI) get(fullURL string, opts ...RequestOption) (io.ReadCloser, int64, error) {
-	o := api.defaultRequestOptions(opts)
+func (api *KSCloudAPI) get(ctx context.Context, fullURL string, opts ...RequestOption) (io.ReadCloser, int64, error) {
+	o := api.defaultRequestOptions(ctx, opts)
 	req, err := http.NewRequestWithContext(o.reqContext, http.MethodGet, fullURL, nil)
 	if err != nil {
 		return nil, 0, err
@@ -393,8 +828,15 @@ func (api *KSCloudAPI) get(fullURL string, opts ...RequestOption) (io.ReadCloser
 	return api.do(req, o)
 }
 
-func (api *KSCloudAPI) post(fullURL string, body []byte, opts ...RequestOption) (io.ReadCloser, int64, error) {
-	o := api.defaultRequestOptions(opts)
+func (api *KSCloudAPI) post(ctx context.Context, fullURL string, body []byte, opts ...RequestOption) (io.ReadCloser, int64, error) {
+	o := api.defaultRequestOptions(ctx, opts)
+	if o.compression != CompressionNone {
+		var err error
+		if body, err = o.compression.compress(body); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	req, err := http.NewRequestWithContext(o.reqContext, http.MethodPost, fullURL, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, 0, err
@@ -403,42 +845,80 @@ func (api *KSCloudAPI) post(fullURL string, body []byte, opts ...RequestOption)
 	return api.do(req, o)
 }
 
-// func (api *KSCloudAPI) delete(fullURL string, opts ...RequestOption) (io.ReadCloser, int64, error) {
-// 	o := api.defaultRequestOptions(opts)
-// 	req, err := http.NewRequestWithContext(o.reqContext, http.MethodDelete, fullURL, nil)
-// 	if err != nil {
-// 		return nil, 0, err
-// 	}
+// postStream posts the JSON encoding of a document, streamed to the server as it is being encoded.
+//
+// Posture reports are encoded one resource and one result at a time, which avoids holding the whole
+// encoded report in memory. Other documents are encoded at once, then streamed.
+// The request may still be retried, as the body is re-encoded for every attempt.
+func (api *KSCloudAPI) postStream(ctx context.Context, fullURL string, doc any, opts ...RequestOption) (io.ReadCloser, int64, error) {
+	o := api.defaultRequestOptions(ctx, opts)
+	req, err := http.NewRequestWithContext(o.reqContext, http.MethodPost, fullURL, encodeJSONStream(doc, o.compression))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.ContentLength = -1
+	req.GetBody = func() (io.ReadCloser, error) {
+		return encodeJSONStream(doc, o.compression), nil
+	}
 
-// 	return api.do(req, o)
-// }
+	return api.do(req, o)
+}
+
+func (api *KSCloudAPI) put(ctx context.Context, fullURL string, body []byte, opts ...RequestOption) (io.ReadCloser, int64, error) {
+	o := api.defaultRequestOptions(ctx, opts)
+	req, err := http.NewRequestWithContext(o.reqContext, http.MethodPut, fullURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return api.do(req, o)
+}
+
+func (api *KSCloudAPI) delete(ctx context.Context, fullURL string, opts ...RequestOption) (io.ReadCloser, int64, error) {
+	o := api.defaultRequestOptions(ctx, opts)
+	req, err := http.NewRequestWithContext(o.reqContext, http.MethodDelete, fullURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return api.do(req, o)
+}
 
 func (api *KSCloudAPI) do(req *http.Request, o *RequestOptions) (io.ReadCloser, int64, error) {
+	resp, err := api.doResponse(req, o)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// doResponse sends a request and returns the raw response, or an error if the API responded with a status >= 400.
+func (api *KSCloudAPI) doResponse(req *http.Request, o *RequestOptions) (*http.Response, error) {
 	o.setHeaders(req)
-	o.traceReq(req)
 
-	resp, err := api.httpClient.Do(req)
+	resp, err := api.doWithRetry(req, o)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-	o.traceResp(resp)
 
 	if resp.StatusCode >= 400 {
-		return nil, 0, utils.ErrAPI(resp)
+		return nil, utils.ErrAPI(resp)
 	}
 
-	return resp.Body, resp.ContentLength, err
+	return resp, nil
 }
 
-func (api *KSCloudAPI) paramsWithGUID() []string {
+func (settings ksCloudSettings) paramsWithGUID() []string {
 	return append(make([]string, 0, 6),
-		v1.QueryParamCustomerGUID, api.getCustomerGUIDFallBack(),
+		v1.QueryParamCustomerGUID, settings.getCustomerGUIDFallBack(),
 	)
 }
 
-func (api *KSCloudAPI) getCustomerGUIDFallBack() string {
-	if api.accountID != "" {
-		return api.accountID
+func (settings ksCloudSettings) getCustomerGUIDFallBack() string {
+	if settings.accountID != "" {
+		return settings.accountID
 	}
 	return v1.KubescapeFallbackCustomerGUID
 }
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"github.com/armosec/armoapi-go/armotypes"
 	"github.com/armosec/armoapi-go/identifiers"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/klauspost/compress/zstd"
 
 	backendServer "github.com/kubescape/backend/pkg/server/v1"
 	"github.com/kubescape/backend/pkg/utils"
@@ -27,6 +29,7 @@ const (
 	// extra mock API routes
 
 	pathTestPost   = "/test-post"
+	pathTestPut    = "/test-put"
 	pathTestDelete = "/test-delete"
 	pathTestGet    = "/test-get"
 )
@@ -78,6 +81,30 @@ func MockAPIServer(t testing.TB, opts ...mockAPIOption) *testServer {
 		echoRequest(w, r)
 	})
 
+	h.HandleFunc(pathTestPut, func(w http.ResponseWriter, r *http.Request) {
+		if !isPut(t, r) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		if !server.AssertAuth(t, r) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if server.WantsError(w) {
+			return
+		}
+
+		if server.WantsGarbled(w) {
+			return
+		}
+
+		echoRequest(w, r)
+	})
+
 	h.HandleFunc(pathTestDelete, func(w http.ResponseWriter, r *http.Request) {
 		if !isDelete(t, r) {
 			w.WriteHeader(http.StatusMethodNotAllowed)
@@ -413,7 +440,18 @@ func mockHandlerAttackTracks(t testing.TB, opts ...mockAPIOption) func(http.Resp
 }
 
 func mockHandlerExceptions(t testing.TB, opts ...mockAPIOption) func(http.ResponseWriter, *http.Request) {
-	return mockHandlerGetWithGUID(t, mockExceptions, opts...)
+	getHandler := mockHandlerGetWithGUID(t, mockExceptions, opts...)
+	writeHandler := mockHandlerWriteWithGUID[armotypes.PostureExceptionPolicy](t, "policyName", opts...)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			getHandler(w, r)
+
+			return
+		}
+
+		writeHandler(w, r)
+	}
 }
 
 func mockHandlerCustomerConfiguration(t testing.TB, opts ...mockAPIOption) func(http.ResponseWriter, *http.Request) {
@@ -501,10 +539,7 @@ func mockHandlerReport(t testing.TB, opts ...mockAPIOption) func(http.ResponseWr
 			w.WriteHeader(http.StatusBadRequest)
 		}
 
-		buf, err := io.ReadAll(r.Body)
-		defer func() {
-			_ = r.Body.Close()
-		}()
+		buf, err := readBody(r)
 
 		if !assert.NoError(t, err) {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -555,10 +590,82 @@ func mockHandlerGetWithGUID[T any](t testing.TB, generator func() T, opts ...moc
 	}
 }
 
+// mockHandlerWriteWithGUID handles POST, PUT and DELETE requests on a resource.
+//
+// POST and PUT respond with the submitted resource. DELETE expects the resource name as a query param.
+func mockHandlerWriteWithGUID[T any](t testing.TB, nameParam string, opts ...mockAPIOption) func(http.ResponseWriter, *http.Request) {
+	o := apiOptions(opts)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodDelete:
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		if !o.AssertAuth(t, r) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if !hasGUID(t, r) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if o.WantsError(w) {
+			return
+		}
+
+		if o.WantsGarbled(w) {
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			if !assert.NotEmptyf(t, r.Form.Get(nameParam), "expected a %s to delete", nameParam) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+
+			return
+		}
+
+		if !isJSON(t, r) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		var doc T
+		if !assert.NoErrorf(t, json.NewDecoder(r.Body).Decode(&doc), "expected payload to unmarshal into %T", doc) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+
+		enc := json.NewEncoder(w)
+		assert.NoErrorf(t, enc.Encode(doc), "expected %T to marshal to JSON", doc)
+	}
+}
+
 func mockHandlerFrameworks(t testing.TB, opts ...mockAPIOption) func(http.ResponseWriter, *http.Request) {
 	o := apiOptions(opts)
+	writeHandler := mockHandlerWriteWithGUID[reporthandling.Framework](t, "frameworkName", opts...)
 
 	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeHandler(w, r)
+
+			return
+		}
+
 		if !isGet(t, r) {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 
@@ -623,6 +730,10 @@ func isPost(t testing.TB, r *http.Request) bool {
 	return assert.Truef(t, strings.EqualFold(http.MethodPost, r.Method), "expected a POST method called, but got %q", r.Method)
 }
 
+func isPut(t testing.TB, r *http.Request) bool {
+	return assert.Truef(t, strings.EqualFold(http.MethodPut, r.Method), "expected a PUT method called, but got %q", r.Method)
+}
+
 func isDelete(t testing.TB, r *http.Request) bool {
 	return assert.Truef(t, strings.EqualFold(http.MethodDelete, r.Method), "expected a DELETE method called, but got %q", r.Method)
 }
@@ -709,6 +820,36 @@ func hasGUID(t testing.TB, r *http.Request) bool {
 	return true
 }
 
+// readBody reads the body of a request, decompressing it according to its Content-Encoding.
+func readBody(r *http.Request) ([]byte, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	var rdr io.Reader = r.Body
+	switch encoding := r.Header.Get("Content-Encoding"); encoding {
+	case "":
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		rdr = gz
+	case "zstd":
+		zr, err := zstd.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		rdr = zr
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %q", encoding)
+	}
+
+	return io.ReadAll(rdr)
+}
+
 func echoHeaders(w http.ResponseWriter, r *http.Request) {
 	for key, vals := range r.Header {
 		for _, val := range vals {
@@ -724,7 +865,17 @@ func echoRequest(w http.ResponseWriter, r *http.Request) {
 
 func echoBody(w http.ResponseWriter, r *http.Request) {
 	defer func() { _ = r.Body.Close() }()
-	_, _ = io.Copy(w, r.Body)
+
+	// the request body is read in full before responding: with chunked requests, the server
+	// would otherwise stop reading the body as soon as the response is started
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	_, _ = w.Write(buf)
 }
 
 func TestFrameworkFile(framework string) string {
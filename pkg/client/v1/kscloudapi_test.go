@@ -1,13 +1,27 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/armosec/armoapi-go/armotypes"
+	backendServer "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/kubescape/opa-utils/reporthandling"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,7 +34,7 @@ var (
 func TestFallBackGUID(t *testing.T) {
 	t.Run("should yield a GUID even though the account ID is not set", func(t *testing.T) {
 		ks := NewEmptyKSCloudAPI()
-		require.NotEmpty(t, ks.getCustomerGUIDFallBack())
+		require.NotEmpty(t, ks.current().getCustomerGUIDFallBack())
 	})
 }
 
@@ -145,25 +159,113 @@ func TestKSCloudAPI(t *testing.T) {
 			})
 		})
 
+		t.Run("with custom frameworks", func(t *testing.T) {
+			mockCustomFramework := func() *Framework {
+				ids := []string{"control-1", "control-5"}
+
+				return &Framework{
+					PortalBase: armotypes.PortalBase{
+						Name: "my-framework",
+					},
+					Controls: []reporthandling.Control{
+						mockControl("control-1"),
+						mockControl("control-5"),
+					},
+					ControlsIDs: &ids,
+				}
+			}
+
+			t.Run("should create Framework", func(t *testing.T) {
+				t.Parallel()
+
+				framework := mockCustomFramework()
+				created, err := ks.CreateFramework(framework)
+				require.NoError(t, err)
+				require.EqualValues(t, framework, created)
+			})
+
+			t.Run("should update Framework", func(t *testing.T) {
+				t.Parallel()
+
+				framework := mockCustomFramework()
+				updated, err := ks.UpdateFramework(framework)
+				require.NoError(t, err)
+				require.EqualValues(t, framework, updated)
+			})
+
+			t.Run("should delete Framework", func(t *testing.T) {
+				t.Parallel()
+
+				require.NoError(t, ks.DeleteFramework("my-framework"))
+			})
+
+			t.Run("should NOT alter native Framework", func(t *testing.T) {
+				t.Parallel()
+
+				framework := mockCustomFramework()
+				framework.Name = "MITRE"
+
+				_, err := ks.CreateFramework(framework)
+				require.ErrorIs(t, err, ErrNativeFramework)
+
+				_, err = ks.UpdateFramework(framework)
+				require.ErrorIs(t, err, ErrNativeFramework)
+
+				require.ErrorIs(t, ks.DeleteFramework("nsa"), ErrNativeFramework)
+			})
+
+			t.Run("should NOT create Framework without a name", func(t *testing.T) {
+				t.Parallel()
+
+				_, err := ks.CreateFramework(&Framework{})
+				require.ErrorIs(t, err, ErrFrameworkName)
+
+				require.ErrorIs(t, ks.DeleteFramework(""), ErrFrameworkName)
+			})
+
+			t.Run("should NOT create Framework with unknown controls", func(t *testing.T) {
+				t.Parallel()
+
+				framework := mockCustomFramework()
+				framework.SubSections = map[string]*reporthandling.FrameworkSubSection{
+					"section": {
+						ControlIDs: []string{"control-1", "control-99"},
+					},
+				}
+
+				_, err := ks.CreateFramework(framework)
+				require.ErrorIs(t, err, ErrUnknownControl)
+				require.Contains(t, err.Error(), "control-99")
+			})
+		})
+
 		t.Run("with controls", func(t *testing.T) {
-			t.Run("should NOT retrieve Control (not a public API)", func(t *testing.T) {
+			t.Run("should retrieve Control", func(t *testing.T) {
 				t.Parallel()
 
-				const id = "control-1"
+				const id = "control-3"
 
 				control, err := ks.GetControl(id)
-				require.Error(t, err)
-				require.Nil(t, control)
-				require.Contains(t, err.Error(), "is not public")
+				require.NoError(t, err)
+				require.NotNil(t, control)
+				require.Equal(t, id, control.ControlID)
+				require.Equal(t, []string{"mock-2"}, control.FrameworkNames)
 			})
 
-			t.Run("should NOT list Controls (not a public API)", func(t *testing.T) {
+			t.Run("should NOT retrieve unknown Control", func(t *testing.T) {
 				t.Parallel()
 
-				control, err := ks.ListControls()
-				require.Error(t, err)
+				control, err := ks.GetControl("control-9")
+				require.ErrorIs(t, err, ErrControlNotFound)
 				require.Nil(t, control)
-				require.Contains(t, err.Error(), "is not public")
+			})
+
+			t.Run("should list Controls", func(t *testing.T) {
+				t.Parallel()
+
+				controls, err := ks.ListControls()
+				require.NoError(t, err)
+				require.Equal(t, []string{"control-1", "control-2", "control-3", "control-4", "control-5", "control-6"}, controls)
 			})
 		})
 
@@ -177,6 +279,46 @@ func TestKSCloudAPI(t *testing.T) {
 				require.Len(t, exceptions, 2)
 				require.EqualValues(t, expected, exceptions)
 			})
+
+			t.Run("should create Exception", func(t *testing.T) {
+				t.Parallel()
+
+				exception := mockExceptions()[0]
+				exception.Name = "my-exception"
+
+				created, err := ks.CreateException(&exception)
+				require.NoError(t, err)
+				require.EqualValues(t, exception, *created)
+			})
+
+			t.Run("should update Exception", func(t *testing.T) {
+				t.Parallel()
+
+				exception := mockExceptions()[1]
+				exception.Name = "my-exception"
+
+				updated, err := ks.UpdateException(&exception)
+				require.NoError(t, err)
+				require.EqualValues(t, exception, *updated)
+			})
+
+			t.Run("should delete Exception", func(t *testing.T) {
+				t.Parallel()
+
+				require.NoError(t, ks.DeleteException("my-exception"))
+			})
+
+			t.Run("should NOT write Exception without a name", func(t *testing.T) {
+				t.Parallel()
+
+				_, err := ks.CreateException(&PostureExceptionPolicy{})
+				require.ErrorIs(t, err, ErrExceptionName)
+
+				_, err = ks.UpdateException(nil)
+				require.ErrorIs(t, err, ErrExceptionName)
+
+				require.ErrorIs(t, ks.DeleteException(""), ErrExceptionName)
+			})
 		})
 
 		t.Run("with CustomerConfig", func(t *testing.T) {
@@ -246,6 +388,68 @@ func TestKSCloudAPI(t *testing.T) {
 		})
 	})
 
+	t.Run("with request helpers", func(t *testing.T) {
+		t.Run("should PUT", func(t *testing.T) {
+			t.Parallel()
+
+			const payload = `{"key":"value"}`
+			rdr, _, err := ks.put(context.Background(), srv.URL(pathTestPut), []byte(payload))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			body, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			require.Equal(t, payload, string(body))
+		})
+
+		t.Run("should DELETE", func(t *testing.T) {
+			t.Parallel()
+
+			rdr, _, err := ks.delete(context.Background(), srv.URL(pathTestDelete))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			body, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			require.Equal(t, "body-delete", string(body))
+		})
+	})
+
+	t.Run("with context", func(t *testing.T) {
+		t.Run("should abort calls with a canceled context", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := ks.GetAttackTracksCtx(ctx)
+			require.ErrorIs(t, err, context.Canceled)
+
+			_, err = ks.GetFrameworksCtx(ctx)
+			require.ErrorIs(t, err, context.Canceled)
+
+			_, err = ks.GetExceptionsCtx(ctx, "")
+			require.ErrorIs(t, err, context.Canceled)
+
+			_, err = ks.GetAccountConfigCtx(ctx, "")
+			require.ErrorIs(t, err, context.Canceled)
+
+			_, err = ks.SubmitReportCtx(ctx, mockPostureReport(t, "5d817063-096f-4d91-b39b-8665240080af", "special-cluster"))
+			require.ErrorIs(t, err, context.Canceled)
+		})
+
+		t.Run("should retrieve Frameworks with a live context", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			frameworks, err := ks.GetFrameworksCtx(ctx)
+			require.NoError(t, err)
+			require.EqualValues(t, mockFrameworks(), frameworks)
+		})
+	})
+
 	t.Run("with getters & setters", func(t *testing.T) {
 
 		kno, err := NewKSCloudAPI(
@@ -262,7 +466,7 @@ func TestKSCloudAPI(t *testing.T) {
 
 		t.Run("should get&set account", func(t *testing.T) {
 			str := pickString()
-			kno.accountID = str
+			kno.SetAccountID(str)
 			require.Equal(t, str, kno.GetAccountID())
 		})
 
@@ -343,6 +547,33 @@ func TestKSCloudAPI(t *testing.T) {
 			_, err = ke.ListCustomFrameworks()
 			require.Error(t, err)
 			require.Contains(t, err.Error(), errAPI.Error())
+
+			exception := mockExceptions()[0]
+			exception.Name = "my-exception"
+
+			_, err = ke.CreateException(&exception)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), errAPI.Error())
+
+			_, err = ke.UpdateException(&exception)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), errAPI.Error())
+
+			err = ke.DeleteException(exception.Name)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), errAPI.Error())
+		})
+
+		t.Run("API errors should be typed", func(t *testing.T) {
+			_, err = ke.GetFrameworks()
+			require.Error(t, err)
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+			require.Equal(t, errAPI.Error(), apiErr.Body)
+			require.Contains(t, apiErr.URL, backendServer.ApiServerFrameworksPath)
+			require.NotErrorIs(t, err, ErrNotFound)
 		})
 	})
 
@@ -397,6 +628,12 @@ func withAPIError(err error) mockAPIOption {
 	}
 }
 
+func withAPIAuth(enabled bool) mockAPIOption {
+	return func(o *mockAPIOptions) {
+		o.withAuth = enabled
+	}
+}
+
 func withAPIGarbled(enabled bool) mockAPIOption {
 	return func(o *mockAPIOptions) {
 		o.withGarbled = enabled
@@ -433,8 +670,261 @@ func TestGetExceptionsURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resultURL := ks.getExceptionsURL(tt.clusterName)
+			resultURL := ks.getExceptionsURL(ks.current(), tt.clusterName)
 			require.Equal(t, tt.expectedURL, resultURL)
 		})
 	}
 }
+
+// largePostureReport inflates the mock posture report to a realistic size for benchmarks.
+func largePostureReport(b *testing.B, factor int) *PostureReport {
+	report := mockPostureReport(b, "", "")
+	resources := report.Resources
+	results := report.Results
+	for i := 1; i < factor; i++ {
+		report.Resources = append(report.Resources, resources...)
+		report.Results = append(report.Results, results...)
+	}
+
+	return report
+}
+
+func BenchmarkSubmitReport(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	b.Cleanup(srv.Close)
+
+	ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "")
+	require.NoError(b, err)
+
+	report := largePostureReport(b, 50)
+	ctx := context.Background()
+	url := ks.postReportURL(ks.current(), report.ClusterName, report.ReportID)
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			jazon, err := json.Marshal(report)
+			require.NoError(b, err)
+
+			rdr, _, err := ks.post(ctx, url, jazon, WithContentJSON(true))
+			require.NoError(b, err)
+			_ = rdr.Close()
+		}
+	})
+
+	b.Run("streamed", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			rdr, _, err := ks.postStream(ctx, url, report, WithContentJSON(true))
+			require.NoError(b, err)
+			_ = rdr.Close()
+		}
+	})
+
+	b.Run("streamed with gzip", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			rdr, _, err := ks.postStream(ctx, url, report, WithContentJSON(true), WithRequestCompression(CompressionGzip))
+			require.NoError(b, err)
+			_ = rdr.Close()
+		}
+	})
+}
+
+func TestAPIErrors(t *testing.T) {
+	t.Parallel()
+
+	const requestID = "6c9a1d1e-1b42-4a8e-9d0e-6ae3b5a1f0c4"
+
+	statusServer := func(t testing.TB, status int) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set(backendServer.RequestIDHeader, requestID)
+			http.Error(w, http.StatusText(status), status)
+		}))
+		t.Cleanup(srv.Close)
+
+		return srv
+	}
+
+	for _, tc := range []struct {
+		status   int
+		sentinel error
+	}{
+		{status: http.StatusUnauthorized, sentinel: ErrUnauthorized},
+		{status: http.StatusForbidden, sentinel: ErrUnauthorized},
+		{status: http.StatusNotFound, sentinel: ErrNotFound},
+		{status: http.StatusTooManyRequests, sentinel: ErrRateLimited},
+	} {
+		t.Run(fmt.Sprintf("should match status %d", tc.status), func(t *testing.T) {
+			t.Parallel()
+
+			srv := statusServer(t, tc.status)
+			ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "")
+			require.NoError(t, err)
+
+			_, err = ks.GetAttackTracks()
+			require.ErrorIs(t, err, tc.sentinel)
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tc.status, apiErr.StatusCode)
+			require.Equal(t, requestID, apiErr.RequestID)
+			require.Equal(t, http.StatusText(tc.status), apiErr.Body)
+		})
+	}
+
+	t.Run("should return typed errors for CVE exceptions", func(t *testing.T) {
+		t.Parallel()
+
+		srv := statusServer(t, http.StatusUnauthorized)
+
+		_, err := GetCVEExceptionByRawQuery(srv.URL, "account", &url.Values{}, nil)
+		require.ErrorIs(t, err, ErrUnauthorized)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, requestID, apiErr.RequestID)
+		require.Contains(t, apiErr.URL, backendServer.ApiServerVulnerabilitiesExceptionsPathOld)
+	})
+}
+
+func TestKSCloudAPIConsistentSettings(t *testing.T) {
+	t.Parallel()
+
+	// server n only accepts the account and access key of the same rotation i, with i%2 == n
+	servers := make([]*httptest.Server, 2)
+	for n := range servers {
+		servers[n] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var i int
+			account := r.URL.Query().Get(backendServer.QueryParamCustomerGUID)
+			if _, err := fmt.Sscanf(account, "account-%d", &i); err != nil ||
+				r.Header.Get(backendServer.AccessKeyHeader) != fmt.Sprintf("key-%d", i) || i%2 != n {
+				http.Error(w, "mismatched settings", http.StatusUnauthorized)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		t.Cleanup(servers[n].Close)
+	}
+
+	// requests queue in the rate limiter between the time their URL is built and the time they are authenticated
+	ks, err := NewKSCloudAPI(servers[0].URL, servers[0].URL, "account-0", "key-0", WithRateLimit("", RateLimit{MaxInFlight: 1}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 1; ctx.Err() == nil; i++ {
+			u, err := url.Parse(servers[i%2].URL)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			ks.updateSettings(func(settings *ksCloudSettings) {
+				settings.accountID = fmt.Sprintf("account-%d", i)
+				settings.accessKey = fmt.Sprintf("key-%d", i)
+				settings.apiScheme, settings.apiHost = u.Scheme, u.Host
+				settings.reportScheme, settings.reportHost = u.Scheme, u.Host
+			})
+		}
+	}()
+
+	report := mockPostureReport(t, "", "")
+	var callers sync.WaitGroup
+	for range 4 {
+		callers.Add(1)
+		go func() {
+			defer callers.Done()
+
+			for range 10 {
+				_, err := ks.GetAttackTracks()
+				assert.NoError(t, err)
+
+				_, err = ks.SubmitReport(report)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	callers.Wait()
+	cancel()
+	wg.Wait()
+}
+
+func TestKSCloudAPIConcurrentRotation(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int64
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if !strings.HasPrefix(r.Header.Get(backendServer.AccessKeyHeader), "key-") ||
+			!strings.HasPrefix(r.URL.Query().Get(backendServer.QueryParamCustomerGUID), "account-") {
+			http.Error(w, "unexpected credentials", http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`[]`))
+	})
+	srv1 := httptest.NewServer(handler)
+	t.Cleanup(srv1.Close)
+	srv2 := httptest.NewServer(handler)
+	t.Cleanup(srv2.Close)
+
+	ks, err := NewKSCloudAPI(srv1.URL, srv1.URL, "account-0", "key-0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; ctx.Err() == nil; i++ {
+			ks.SetAccountID(fmt.Sprintf("account-%d", i))
+			ks.SetAccessKey(fmt.Sprintf("key-%d", i))
+
+			srv := srv1
+			if i%2 == 1 {
+				srv = srv2
+			}
+			assert.NoError(t, ks.SetCloudAPIURL(srv.URL))
+			assert.NoError(t, ks.SetCloudReportURL(srv.URL))
+		}
+	}()
+
+	var callers sync.WaitGroup
+	for range 8 {
+		callers.Add(1)
+		go func() {
+			defer callers.Done()
+
+			for range 20 {
+				_, err := ks.GetAttackTracks()
+				assert.NoError(t, err)
+
+				apiURL := ks.GetCloudAPIURL()
+				assert.True(t, apiURL == srv1.URL || apiURL == srv2.URL, "unexpected API URL: %s", apiURL)
+			}
+		}()
+	}
+
+	callers.Wait()
+	cancel()
+	wg.Wait()
+
+	require.Equal(t, int64(8*20), requests.Load())
+}
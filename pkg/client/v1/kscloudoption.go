@@ -6,6 +6,9 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type (
@@ -14,9 +17,19 @@ type (
 
 	// ksCloudOptions holds all the configurable parts of the KS Cloud client.
 	KsCloudOptions struct {
-		httpClient *http.Client
-		timeout    *time.Duration
-		withTrace  bool
+		httpClient        *http.Client
+		timeout           *time.Duration
+		withTrace         bool
+		retryPolicy       *RetryPolicy
+		responseCache     ResponseCache
+		reportCompression Compression
+		reportChunkSize   int
+		rateLimiters      []*routeLimiter
+		circuitBreakers   *circuitBreakers
+		tracerProvider    trace.TracerProvider
+		meterProvider     metric.MeterProvider
+		telemetry         *telemetry
+		authenticator     IAuthenticator
 	}
 
 	// request option instructs post/get/delete to alter the outgoing request
@@ -24,10 +37,14 @@ type (
 
 	// RequestOptions knows how to enrich a request with headers
 	RequestOptions struct {
-		withJSON   bool
-		withTrace  bool
-		headers    map[string]string
-		reqContext context.Context
+		withJSON    bool
+		withTrace   bool
+		idempotent  bool
+		compression Compression
+		chunkSize   int
+		headers     map[string]string
+		reqContext  context.Context
+		settings    *ksCloudSettings
 	}
 )
 
@@ -60,6 +77,123 @@ func WithTrace(enabled bool) KSCloudOption {
 	}
 }
 
+// WithRetryPolicy enables retries of failed requests, according to the given policy.
+//
+// GET requests and idempotent requests (e.g. report submissions) are retried.
+// A nil policy disables retries. Unset fields of the policy take the value of DefaultRetryPolicy.
+//
+// The default is to make a single attempt.
+func WithRetryPolicy(policy *RetryPolicy) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		if policy == nil {
+			o.retryPolicy = nil
+
+			return
+		}
+
+		o.retryPolicy = policy.withDefaults()
+	}
+}
+
+// WithResponseCache enables caching of frameworks, attack tracks and exceptions.
+//
+// Cached responses are revalidated with conditional requests (If-None-Match, If-Modified-Since)
+// and served from the cache whenever the server responds 304 Not Modified.
+//
+// See NewMemoryResponseCache and NewDiskResponseCache.
+func WithResponseCache(cache ResponseCache) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		o.responseCache = cache
+	}
+}
+
+// WithReportCompression compresses posture report submissions with gzip or zstd.
+//
+// The default is to send uncompressed reports.
+func WithReportCompression(compression Compression) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		o.reportCompression = compression
+	}
+}
+
+// WithReportChunking splits posture report submissions into chunks of at most maxChunkSize bytes
+// of resources and results, sent as several requests sharing the same report GUID.
+//
+// A value of 0 disables chunking. This is the default.
+func WithReportChunking(maxChunkSize int) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		o.reportChunkSize = maxChunkSize
+	}
+}
+
+// WithRateLimit throttles requests to the routes starting with routePrefix, e.g. RouteAPI or RouteReport.
+//
+// When several prefixes match a request, the longest one applies. An empty prefix matches all routes.
+// Each prefix is throttled independently, so that posture report submissions do not starve API calls.
+//
+// The rate adapts to the server: it is lowered whenever the server responds 429 Too Many Requests,
+// and requests are held for the delay advertised by the Retry-After header.
+//
+// The default is not to throttle requests.
+func WithRateLimit(routePrefix string, limit RateLimit) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		limiters := make([]*routeLimiter, 0, len(o.rateLimiters)+1)
+		for _, l := range o.rateLimiters {
+			if l.prefix != routePrefix {
+				limiters = append(limiters, l)
+			}
+		}
+
+		o.rateLimiters = append(limiters, newRouteLimiter(routePrefix, limit))
+	}
+}
+
+// WithCircuitBreaker protects each host called by the client (API server and report receiver)
+// with a circuit breaker.
+//
+// When a host fails repeatedly, calls to this host fail fast with ErrCircuitOpen until the host recovers.
+//
+// The default is not to use circuit breakers.
+func WithCircuitBreaker(config CircuitBreakerConfig) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		o.circuitBreakers = &circuitBreakers{
+			config:   config,
+			breakers: make(map[string]*CircuitBreaker),
+		}
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used to trace requests.
+//
+// The W3C trace context is propagated to the backend. The default is to use the global tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the OpenTelemetry meter provider used to record request metrics.
+//
+// The default is to use the global meter provider.
+func WithMeterProvider(mp metric.MeterProvider) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		o.meterProvider = mp
+	}
+}
+
+// WithAuthenticator sets how requests are authenticated, e.g. with a bearer token file or OAuth2 client credentials.
+//
+// The authenticator supersedes the access key passed to the client. Access keys later set with SetAccessKey,
+// e.g. by WatchCredentials, are forwarded to an authenticator implementing SetAccessKey(string), such as
+// StaticKeyAuthenticator, and are otherwise not sent.
+//
+// The default is to send the access key, if any, in the X-API-KEY header.
+func WithAuthenticator(authenticator IAuthenticator) KSCloudOption {
+	return func(o *KsCloudOptions) {
+		o.authenticator = authenticator
+	}
+}
+
 var defaultClient = &http.Client{
 	Timeout: 61 * time.Second,
 }
@@ -82,6 +216,8 @@ func ksCloudOptionsWithDefaults(opts []KSCloudOption) *KsCloudOptions {
 		options.httpClient = &client
 	}
 
+	options.telemetry = newTelemetry("kscloud.client", options.tracerProvider, options.meterProvider)
+
 	return options
 }
 
@@ -101,6 +237,27 @@ func WithHeaders(headers map[string]string) RequestOption {
 	}
 }
 
+// WithIdempotent flags a request as idempotent, so it may be retried safely
+func WithIdempotent(enabled bool) RequestOption {
+	return func(o *RequestOptions) {
+		o.idempotent = enabled
+	}
+}
+
+// WithRequestCompression compresses the body of a request
+func WithRequestCompression(compression Compression) RequestOption {
+	return func(o *RequestOptions) {
+		o.compression = compression
+	}
+}
+
+// WithRequestChunking splits a posture report submission into chunks of at most maxChunkSize bytes
+func WithRequestChunking(maxChunkSize int) RequestOption {
+	return func(o *RequestOptions) {
+		o.chunkSize = maxChunkSize
+	}
+}
+
 // withTrace dumps requests for debugging
 func withTrace(enabled bool) RequestOption {
 	return func(o *RequestOptions) {
@@ -108,11 +265,31 @@ func withTrace(enabled bool) RequestOption {
 	}
 }
 
+// withSettings sends the request with a snapshot of the settings of the client, which its URL was built from.
+func withSettings(settings ksCloudSettings) RequestOption {
+	return func(o *RequestOptions) {
+		o.settings = &settings
+	}
+}
+
+// withContext sets the context for a request, to carry cancellation, deadlines and tracing
+func withContext(ctx context.Context) RequestOption {
+	return func(o *RequestOptions) {
+		if ctx != nil {
+			o.reqContext = ctx
+		}
+	}
+}
+
 func (o *RequestOptions) setHeaders(req *http.Request) {
 	if o.withJSON {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	if o.compression != CompressionNone {
+		req.Header.Set("Content-Encoding", string(o.compression))
+	}
+
 	for k, v := range o.headers {
 		req.Header.Set(k, v)
 	}
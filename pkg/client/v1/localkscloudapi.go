@@ -0,0 +1,257 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kubescape/backend/pkg/utils"
+)
+
+// Layout of the directory tree used by LocalKSCloudAPI.
+const (
+	LocalFrameworksDir      = "frameworks"
+	LocalControlsDir        = "controls"
+	LocalAttackTracksDir    = "attackTracks"
+	LocalReportsDir         = "reports"
+	LocalExceptionsFile     = "exceptions.json"
+	LocalCustomerConfigFile = "customerConfig.json"
+)
+
+// LocalKSCloudAPI serves policies from a local directory tree and writes posture reports to disk.
+//
+// The expected layout is:
+//
+//	<root>/frameworks/*.json      one Framework per file
+//	<root>/controls/*.json        one Control per file
+//	<root>/attackTracks/*.json    one AttackTrack per file
+//	<root>/exceptions.json        a list of PostureExceptionPolicy
+//	<root>/customerConfig.json    a CustomerConfig
+//	<root>/reports/               submitted posture reports
+//
+// Missing files or directories are considered empty.
+type LocalKSCloudAPI struct {
+	root string
+}
+
+// NewLocalKSCloudAPI creates a LocalKSCloudAPI rooted at the given directory.
+func NewLocalKSCloudAPI(root string) *LocalKSCloudAPI {
+	return &LocalKSCloudAPI{
+		root: root,
+	}
+}
+
+// GetRoot returns the root directory.
+func (api *LocalKSCloudAPI) GetRoot() string { return api.root }
+
+// GetAttackTracks retrieves all attack tracks.
+func (api *LocalKSCloudAPI) GetAttackTracks() ([]AttackTrack, error) {
+	return readLocalDir[AttackTrack](filepath.Join(api.root, LocalAttackTracksDir))
+}
+
+// GetFramework retrieves a framework by name.
+//
+// Framework names are matched regardless of case.
+func (api *LocalKSCloudAPI) GetFramework(frameworkName string) (*Framework, error) {
+	frameworks, err := api.GetFrameworks()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range frameworks {
+		if strings.EqualFold(frameworks[i].Name, frameworkName) {
+			return &frameworks[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrFrameworkNotFound, frameworkName)
+}
+
+// GetFrameworks returns all frameworks.
+func (api *LocalKSCloudAPI) GetFrameworks() ([]Framework, error) {
+	return readLocalDir[Framework](filepath.Join(api.root, LocalFrameworksDir))
+}
+
+// ListCustomFrameworks lists the names of all non-native frameworks.
+func (api *LocalKSCloudAPI) ListCustomFrameworks() ([]string, error) {
+	frameworks, err := api.GetFrameworks()
+	if err != nil {
+		return nil, err
+	}
+
+	frameworkList := make([]string, 0, len(frameworks))
+	for _, framework := range frameworks {
+		if utils.IsNativeFramework(framework.Name) {
+			continue
+		}
+
+		frameworkList = append(frameworkList, framework.Name)
+	}
+
+	return frameworkList, nil
+}
+
+// ListFrameworks list the names of all frameworks.
+func (api *LocalKSCloudAPI) ListFrameworks() ([]string, error) {
+	frameworks, err := api.GetFrameworks()
+	if err != nil {
+		return nil, err
+	}
+
+	frameworkList := make([]string, 0, len(frameworks))
+	for _, framework := range frameworks {
+		name := framework.Name
+		if utils.IsNativeFramework(framework.Name) {
+			name = strings.ToLower(framework.Name)
+		}
+
+		frameworkList = append(frameworkList, name)
+	}
+
+	return frameworkList, nil
+}
+
+// GetControl retrieves a control by ID.
+func (api *LocalKSCloudAPI) GetControl(ID string) (*Control, error) {
+	controls, err := readLocalDir[Control](filepath.Join(api.root, LocalControlsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range controls {
+		if controls[i].ControlID == ID {
+			return &controls[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrControlNotFound, ID)
+}
+
+// ListControls lists the IDs of all controls.
+func (api *LocalKSCloudAPI) ListControls() ([]string, error) {
+	controls, err := readLocalDir[Control](filepath.Join(api.root, LocalControlsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	controlList := make([]string, 0, len(controls))
+	for _, control := range controls {
+		controlList = append(controlList, control.ControlID)
+	}
+
+	return controlList, nil
+}
+
+// GetExceptions returns exception policies.
+//
+// The cluster name is ignored.
+func (api *LocalKSCloudAPI) GetExceptions(_ string) ([]PostureExceptionPolicy, error) {
+	exceptions, err := readLocalFile[[]PostureExceptionPolicy](filepath.Join(api.root, LocalExceptionsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return exceptions, nil
+}
+
+// GetAccountConfig yields the account configuration.
+//
+// The cluster name is ignored.
+func (api *LocalKSCloudAPI) GetAccountConfig(_ string) (*CustomerConfig, error) {
+	accountConfig, err := readLocalFile[CustomerConfig](filepath.Join(api.root, LocalCustomerConfigFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountConfig, nil
+}
+
+// GetControlsInputs returns the controls inputs configured in the account configuration.
+func (api *LocalKSCloudAPI) GetControlsInputs(clusterName string) (map[string][]string, error) {
+	accountConfig, err := api.GetAccountConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountConfig.Settings.PostureControlInputs, nil
+}
+
+// SubmitReport writes a posture report as a JSON file in the reports directory.
+//
+// It returns the path to the written file.
+func (api *LocalKSCloudAPI) SubmitReport(report *PostureReport) (string, error) {
+	dir := filepath.Join(api.root, LocalReportsDir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+
+	reportID := report.ReportID
+	if reportID == "" {
+		reportID = uuid.NewString()
+	}
+
+	jazon, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+
+	pth := filepath.Join(dir, filepath.Base(reportID)+".json")
+	if err := os.WriteFile(pth, jazon, 0o600); err != nil {
+		return "", err
+	}
+
+	return pth, nil
+}
+
+// readLocalFile decodes a JSON file. A missing file yields the zero value.
+func readLocalFile[T any](pth string) (T, error) {
+	var empty T
+
+	f, err := os.Open(pth)
+	if errors.Is(err, fs.ErrNotExist) {
+		return empty, nil
+	}
+	if err != nil {
+		return empty, err
+	}
+	defer f.Close()
+
+	doc, err := utils.Decode[T](f)
+	if err != nil {
+		return empty, fmt.Errorf("invalid JSON file %s: %w", pth, err)
+	}
+
+	return doc, nil
+}
+
+// readLocalDir decodes all JSON files in a directory, in file name order. A missing directory yields an empty list.
+func readLocalDir[T any](dir string) ([]T, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []T{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		doc, err := readLocalFile[T](filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
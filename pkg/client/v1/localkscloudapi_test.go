@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockLocalTree writes the mock fixtures as a directory tree suitable for LocalKSCloudAPI.
+func mockLocalTree(t testing.TB) string {
+	root := t.TempDir()
+
+	writeJSON := func(pth string, doc any) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0o750))
+		buf, err := json.Marshal(doc)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(pth, buf, 0o600))
+	}
+
+	for _, framework := range mockFrameworks() {
+		writeJSON(filepath.Join(root, LocalFrameworksDir, framework.Name+".json"), framework)
+	}
+	for _, id := range []string{"control-1", "control-2"} {
+		writeJSON(filepath.Join(root, LocalControlsDir, id+".json"), mockControl(id))
+	}
+	for i, track := range mockAttackTracks() {
+		writeJSON(filepath.Join(root, LocalAttackTracksDir, string(rune('a'+i))+".json"), track)
+	}
+	writeJSON(filepath.Join(root, LocalExceptionsFile), mockExceptions())
+	writeJSON(filepath.Join(root, LocalCustomerConfigFile), mockCustomerConfig("", "")())
+
+	return root
+}
+
+func TestLocalKSCloudAPI(t *testing.T) {
+	t.Parallel()
+
+	ks := NewLocalKSCloudAPI(mockLocalTree(t))
+
+	t.Run("should retrieve Frameworks", func(t *testing.T) {
+		t.Parallel()
+
+		frameworks, err := ks.GetFrameworks()
+		require.NoError(t, err)
+		require.EqualValues(t, mockFrameworks(), frameworks)
+
+		framework, err := ks.GetFramework("NSA")
+		require.NoError(t, err)
+		require.Equal(t, "nsa", framework.Name)
+
+		_, err = ks.GetFramework("unknown")
+		require.ErrorIs(t, err, ErrFrameworkNotFound)
+
+		names, err := ks.ListFrameworks()
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"mock-1", "mock-2", "nsa"}, names)
+
+		names, err = ks.ListCustomFrameworks()
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"mock-1", "mock-2"}, names)
+	})
+
+	t.Run("should retrieve Controls", func(t *testing.T) {
+		t.Parallel()
+
+		control, err := ks.GetControl("control-2")
+		require.NoError(t, err)
+		require.Equal(t, "control-2", control.ControlID)
+
+		_, err = ks.GetControl("control-9")
+		require.ErrorIs(t, err, ErrControlNotFound)
+
+		ids, err := ks.ListControls()
+		require.NoError(t, err)
+		require.Equal(t, []string{"control-1", "control-2"}, ids)
+	})
+
+	t.Run("should retrieve AttackTracks", func(t *testing.T) {
+		t.Parallel()
+
+		tracks, err := ks.GetAttackTracks()
+		require.NoError(t, err)
+		require.Len(t, tracks, 2)
+	})
+
+	t.Run("should retrieve Exceptions and CustomerConfig", func(t *testing.T) {
+		t.Parallel()
+
+		exceptions, err := ks.GetExceptions("")
+		require.NoError(t, err)
+		require.EqualValues(t, mockExceptions(), exceptions)
+
+		account, err := ks.GetAccountConfig("")
+		require.NoError(t, err)
+		require.EqualValues(t, mockCustomerConfig("", "")(), account)
+
+		inputs, err := ks.GetControlsInputs("")
+		require.NoError(t, err)
+		require.EqualValues(t, account.Settings.PostureControlInputs, inputs)
+	})
+
+	t.Run("should write report to disk", func(t *testing.T) {
+		t.Parallel()
+
+		const reportID = "5d817063-096f-4d91-b39b-8665240080af"
+		report := mockPostureReport(t, reportID, "special-cluster")
+		report.ReportID = reportID
+
+		pth, err := ks.SubmitReport(report)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(ks.GetRoot(), LocalReportsDir, reportID+".json"), pth)
+
+		buf, err := os.ReadFile(pth)
+		require.NoError(t, err)
+
+		var written PostureReport
+		require.NoError(t, json.Unmarshal(buf, &written))
+		require.Equal(t, reportID, written.ReportID)
+	})
+
+	t.Run("should tolerate an empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		empty := NewLocalKSCloudAPI(t.TempDir())
+
+		frameworks, err := empty.GetFrameworks()
+		require.NoError(t, err)
+		require.Empty(t, frameworks)
+
+		exceptions, err := empty.GetExceptions("")
+		require.NoError(t, err)
+		require.Empty(t, exceptions)
+
+		account, err := empty.GetAccountConfig("")
+		require.NoError(t, err)
+		require.Empty(t, *account)
+	})
+}
@@ -33,6 +33,7 @@ const (
 	ErrorCode_ERROR_CODE_PROFILE_NOT_FOUND ErrorCode = 5
 	ErrorCode_ERROR_CODE_INTERNAL_ERROR    ErrorCode = 6
 	ErrorCode_ERROR_CODE_PULSAR_ERROR      ErrorCode = 7
+	ErrorCode_ERROR_CODE_RESOURCE_EXPIRED  ErrorCode = 8
 )
 
 var ErrorCode_name = map[int32]string{
@@ -44,6 +45,7 @@ var ErrorCode_name = map[int32]string{
 	5: "ERROR_CODE_PROFILE_NOT_FOUND",
 	6: "ERROR_CODE_INTERNAL_ERROR",
 	7: "ERROR_CODE_PULSAR_ERROR",
+	8: "ERROR_CODE_RESOURCE_EXPIRED",
 }
 
 var ErrorCode_value = map[string]int32{
@@ -55,6 +57,7 @@ var ErrorCode_value = map[string]int32{
 	"ERROR_CODE_PROFILE_NOT_FOUND": 5,
 	"ERROR_CODE_INTERNAL_ERROR":    6,
 	"ERROR_CODE_PULSAR_ERROR":      7,
+	"ERROR_CODE_RESOURCE_EXPIRED":  8,
 }
 
 func (x ErrorCode) String() string {
@@ -65,6 +68,44 @@ func (ErrorCode) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_3d90829bc66d9c54, []int{0}
 }
 
+// WatchEventType is the type of a watch event, as in Kubernetes
+type WatchEventType int32
+
+const (
+	WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED WatchEventType = 0
+	WatchEventType_WATCH_EVENT_TYPE_ADDED       WatchEventType = 1
+	WatchEventType_WATCH_EVENT_TYPE_MODIFIED    WatchEventType = 2
+	WatchEventType_WATCH_EVENT_TYPE_DELETED     WatchEventType = 3
+	WatchEventType_WATCH_EVENT_TYPE_BOOKMARK    WatchEventType = 4
+	WatchEventType_WATCH_EVENT_TYPE_ERROR       WatchEventType = 5
+)
+
+var WatchEventType_name = map[int32]string{
+	0: "WATCH_EVENT_TYPE_UNSPECIFIED",
+	1: "WATCH_EVENT_TYPE_ADDED",
+	2: "WATCH_EVENT_TYPE_MODIFIED",
+	3: "WATCH_EVENT_TYPE_DELETED",
+	4: "WATCH_EVENT_TYPE_BOOKMARK",
+	5: "WATCH_EVENT_TYPE_ERROR",
+}
+
+var WatchEventType_value = map[string]int32{
+	"WATCH_EVENT_TYPE_UNSPECIFIED": 0,
+	"WATCH_EVENT_TYPE_ADDED":       1,
+	"WATCH_EVENT_TYPE_MODIFIED":    2,
+	"WATCH_EVENT_TYPE_DELETED":     3,
+	"WATCH_EVENT_TYPE_BOOKMARK":    4,
+	"WATCH_EVENT_TYPE_ERROR":       5,
+}
+
+func (x WatchEventType) String() string {
+	return proto.EnumName(WatchEventType_name, int32(x))
+}
+
+func (WatchEventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{1}
+}
+
 // SendContainerProfileRequest contains the container profile to be stored
 // customer_guid, cluster, host_type, and host_id are sent via gRPC metadata headers
 type SendContainerProfileRequest struct {
@@ -167,7 +208,7 @@ func (m *SendContainerProfileResponse) GetErrorCode() ErrorCode {
 // GetProfileRequest requests an aggregated profile
 // customer_guid, cluster, host_type, and host_id are sent via gRPC metadata headers
 type GetProfileRequest struct {
-	// Kind specifies the type of profile: "applicationProfile" or "networkNeighborhood"
+	// Kind specifies the type of profile: "applicationProfile", "networkNeighborhood", or "containerProfile"
 	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
 	// Namespace of the workload (k8s scope identifier)
 	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
@@ -338,10 +379,17 @@ type ListApplicationProfilesRequest struct {
 	// Region of the resource (non-k8s scope identifier, e.g. "us-east-1")
 	Region string `protobuf:"bytes,6,opt,name=region,proto3" json:"region,omitempty"`
 	// CloudAccountIdentifier of the resource (non-k8s scope identifier, e.g. AWS account ID "123456789012", GCP project ID)
-	CloudAccountIdentifier string   `protobuf:"bytes,7,opt,name=cloud_account_identifier,json=cloudAccountIdentifier,proto3" json:"cloud_account_identifier,omitempty"`
-	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
-	XXX_unrecognized       []byte   `json:"-"`
-	XXX_sizecache          int32    `json:"-"`
+	CloudAccountIdentifier string `protobuf:"bytes,7,opt,name=cloud_account_identifier,json=cloudAccountIdentifier,proto3" json:"cloud_account_identifier,omitempty"`
+	// LabelSelector restricts the list to the profiles matching a Kubernetes label selector (e.g. "app=nginx,tier in (web)")
+	LabelSelector string `protobuf:"bytes,8,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	// FieldSelector restricts the list to the profiles matching a Kubernetes field selector (e.g. "workloadKind=Deployment,status!=completed")
+	// Supported fields: metadata.name, metadata.namespace, workloadKind, status, and completion
+	FieldSelector string `protobuf:"bytes,9,opt,name=field_selector,json=fieldSelector,proto3" json:"field_selector,omitempty"`
+	// AllNamespaces lists the profiles of all namespaces (namespace must be empty)
+	AllNamespaces        bool     `protobuf:"varint,10,opt,name=all_namespaces,json=allNamespaces,proto3" json:"all_namespaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ListApplicationProfilesRequest) Reset()         { *m = ListApplicationProfilesRequest{} }
@@ -403,6 +451,27 @@ func (m *ListApplicationProfilesRequest) GetCloudAccountIdentifier() string {
 	return ""
 }
 
+func (m *ListApplicationProfilesRequest) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+func (m *ListApplicationProfilesRequest) GetFieldSelector() string {
+	if m != nil {
+		return m.FieldSelector
+	}
+	return ""
+}
+
+func (m *ListApplicationProfilesRequest) GetAllNamespaces() bool {
+	if m != nil {
+		return m.AllNamespaces
+	}
+	return false
+}
+
 // ListApplicationProfilesResponse contains the list of ApplicationProfiles (with nil Spec)
 type ListApplicationProfilesResponse struct {
 	// Success indicates if the list was successfully retrieved
@@ -491,10 +560,17 @@ type ListNetworkNeighborhoodsRequest struct {
 	// Region of the resource (non-k8s scope identifier, e.g. "us-east-1")
 	Region string `protobuf:"bytes,6,opt,name=region,proto3" json:"region,omitempty"`
 	// CloudAccountIdentifier of the resource (non-k8s scope identifier, e.g. AWS account ID "123456789012", GCP project ID)
-	CloudAccountIdentifier string   `protobuf:"bytes,7,opt,name=cloud_account_identifier,json=cloudAccountIdentifier,proto3" json:"cloud_account_identifier,omitempty"`
-	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
-	XXX_unrecognized       []byte   `json:"-"`
-	XXX_sizecache          int32    `json:"-"`
+	CloudAccountIdentifier string `protobuf:"bytes,7,opt,name=cloud_account_identifier,json=cloudAccountIdentifier,proto3" json:"cloud_account_identifier,omitempty"`
+	// LabelSelector restricts the list to the neighborhoods matching a Kubernetes label selector (e.g. "app=nginx,tier in (web)")
+	LabelSelector string `protobuf:"bytes,8,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	// FieldSelector restricts the list to the neighborhoods matching a Kubernetes field selector (e.g. "workloadKind=Deployment,status!=completed")
+	// Supported fields: metadata.name, metadata.namespace, workloadKind, status, and completion
+	FieldSelector string `protobuf:"bytes,9,opt,name=field_selector,json=fieldSelector,proto3" json:"field_selector,omitempty"`
+	// AllNamespaces lists the neighborhoods of all namespaces (namespace must be empty)
+	AllNamespaces        bool     `protobuf:"varint,10,opt,name=all_namespaces,json=allNamespaces,proto3" json:"all_namespaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ListNetworkNeighborhoodsRequest) Reset()         { *m = ListNetworkNeighborhoodsRequest{} }
@@ -556,6 +632,27 @@ func (m *ListNetworkNeighborhoodsRequest) GetCloudAccountIdentifier() string {
 	return ""
 }
 
+func (m *ListNetworkNeighborhoodsRequest) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+func (m *ListNetworkNeighborhoodsRequest) GetFieldSelector() string {
+	if m != nil {
+		return m.FieldSelector
+	}
+	return ""
+}
+
+func (m *ListNetworkNeighborhoodsRequest) GetAllNamespaces() bool {
+	if m != nil {
+		return m.AllNamespaces
+	}
+	return false
+}
+
 // ListNetworkNeighborhoodsResponse contains the list of NetworkNeighborhoods (with nil Spec)
 type ListNetworkNeighborhoodsResponse struct {
 	// Success indicates if the list was successfully retrieved
@@ -632,8 +729,634 @@ func (m *ListNetworkNeighborhoodsResponse) GetCont() string {
 	return ""
 }
 
+// StreamContainerProfilesRequest contains one container profile of a stream
+// customer_guid, cluster, host_type, and host_id are sent via gRPC metadata headers
+type StreamContainerProfilesRequest struct {
+	// ContainerProfile is the time-series container profile from node agent
+	ContainerProfile *v1beta1.ContainerProfile `protobuf:"bytes,1,opt,name=container_profile,json=containerProfile,proto3" json:"container_profile,omitempty"`
+	// Index identifies the profile within the stream, to match its acknowledgement
+	Index                int64    `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamContainerProfilesRequest) Reset()         { *m = StreamContainerProfilesRequest{} }
+func (m *StreamContainerProfilesRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamContainerProfilesRequest) ProtoMessage()    {}
+func (*StreamContainerProfilesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{8}
+}
+func (m *StreamContainerProfilesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamContainerProfilesRequest.Unmarshal(m, b)
+}
+func (m *StreamContainerProfilesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamContainerProfilesRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamContainerProfilesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamContainerProfilesRequest.Merge(m, src)
+}
+func (m *StreamContainerProfilesRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamContainerProfilesRequest.Size(m)
+}
+func (m *StreamContainerProfilesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamContainerProfilesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamContainerProfilesRequest proto.InternalMessageInfo
+
+func (m *StreamContainerProfilesRequest) GetContainerProfile() *v1beta1.ContainerProfile {
+	if m != nil {
+		return m.ContainerProfile
+	}
+	return nil
+}
+
+func (m *StreamContainerProfilesRequest) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+// ContainerProfileAck indicates success or failure for one container profile of a stream
+type ContainerProfileAck struct {
+	// Index of the profile within the stream
+	Index int64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Success indicates if the profile was successfully sent to Pulsar
+	Success bool `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	// Error message if the operation failed
+	ErrorMessage string `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Error code for programmatic error handling
+	ErrorCode            ErrorCode `protobuf:"varint,4,opt,name=error_code,json=errorCode,proto3,enum=storageserver.v1.ErrorCode" json:"error_code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ContainerProfileAck) Reset()         { *m = ContainerProfileAck{} }
+func (m *ContainerProfileAck) String() string { return proto.CompactTextString(m) }
+func (*ContainerProfileAck) ProtoMessage()    {}
+func (*ContainerProfileAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{9}
+}
+func (m *ContainerProfileAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContainerProfileAck.Unmarshal(m, b)
+}
+func (m *ContainerProfileAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContainerProfileAck.Marshal(b, m, deterministic)
+}
+func (m *ContainerProfileAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContainerProfileAck.Merge(m, src)
+}
+func (m *ContainerProfileAck) XXX_Size() int {
+	return xxx_messageInfo_ContainerProfileAck.Size(m)
+}
+func (m *ContainerProfileAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContainerProfileAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContainerProfileAck proto.InternalMessageInfo
+
+func (m *ContainerProfileAck) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ContainerProfileAck) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *ContainerProfileAck) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+func (m *ContainerProfileAck) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+// StreamContainerProfilesResponse acknowledges the container profiles of a stream
+type StreamContainerProfilesResponse struct {
+	// Success indicates if all the profiles were successfully sent to Pulsar
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Error message if the operation failed
+	ErrorMessage string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Error code for programmatic error handling
+	ErrorCode ErrorCode `protobuf:"varint,3,opt,name=error_code,json=errorCode,proto3,enum=storageserver.v1.ErrorCode" json:"error_code,omitempty"`
+	// Acks holds the acknowledgement of each profile received
+	Acks                 []*ContainerProfileAck `protobuf:"bytes,4,rep,name=acks,proto3" json:"acks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *StreamContainerProfilesResponse) Reset()         { *m = StreamContainerProfilesResponse{} }
+func (m *StreamContainerProfilesResponse) String() string { return proto.CompactTextString(m) }
+func (*StreamContainerProfilesResponse) ProtoMessage()    {}
+func (*StreamContainerProfilesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{10}
+}
+func (m *StreamContainerProfilesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamContainerProfilesResponse.Unmarshal(m, b)
+}
+func (m *StreamContainerProfilesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamContainerProfilesResponse.Marshal(b, m, deterministic)
+}
+func (m *StreamContainerProfilesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamContainerProfilesResponse.Merge(m, src)
+}
+func (m *StreamContainerProfilesResponse) XXX_Size() int {
+	return xxx_messageInfo_StreamContainerProfilesResponse.Size(m)
+}
+func (m *StreamContainerProfilesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamContainerProfilesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamContainerProfilesResponse proto.InternalMessageInfo
+
+func (m *StreamContainerProfilesResponse) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *StreamContainerProfilesResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+func (m *StreamContainerProfilesResponse) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+func (m *StreamContainerProfilesResponse) GetAcks() []*ContainerProfileAck {
+	if m != nil {
+		return m.Acks
+	}
+	return nil
+}
+
+// WatchProfilesRequest requests the changes of ApplicationProfiles or NetworkNeighborhoods in a namespace
+// customer_guid, cluster, host_type, and host_id are sent via gRPC metadata headers
+type WatchProfilesRequest struct {
+	// Kind specifies the type of profile: "ApplicationProfile" or "NetworkNeighborhood"
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Namespace to watch profiles in
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// ResourceVersion after which changes are sent (empty means from the current state)
+	ResourceVersion string `protobuf:"bytes,3,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	// AllowBookmarks requests BOOKMARK events carrying the latest resource version
+	AllowBookmarks bool `protobuf:"varint,4,opt,name=allow_bookmarks,json=allowBookmarks,proto3" json:"allow_bookmarks,omitempty"`
+	// Region of the resource (non-k8s scope identifier, e.g. "us-east-1")
+	Region string `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	// CloudAccountIdentifier of the resource (non-k8s scope identifier, e.g. AWS account ID "123456789012", GCP project ID)
+	CloudAccountIdentifier string   `protobuf:"bytes,6,opt,name=cloud_account_identifier,json=cloudAccountIdentifier,proto3" json:"cloud_account_identifier,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
+}
+
+func (m *WatchProfilesRequest) Reset()         { *m = WatchProfilesRequest{} }
+func (m *WatchProfilesRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchProfilesRequest) ProtoMessage()    {}
+func (*WatchProfilesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{11}
+}
+func (m *WatchProfilesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchProfilesRequest.Unmarshal(m, b)
+}
+func (m *WatchProfilesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchProfilesRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchProfilesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchProfilesRequest.Merge(m, src)
+}
+func (m *WatchProfilesRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchProfilesRequest.Size(m)
+}
+func (m *WatchProfilesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchProfilesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchProfilesRequest proto.InternalMessageInfo
+
+func (m *WatchProfilesRequest) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *WatchProfilesRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WatchProfilesRequest) GetResourceVersion() string {
+	if m != nil {
+		return m.ResourceVersion
+	}
+	return ""
+}
+
+func (m *WatchProfilesRequest) GetAllowBookmarks() bool {
+	if m != nil {
+		return m.AllowBookmarks
+	}
+	return false
+}
+
+func (m *WatchProfilesRequest) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *WatchProfilesRequest) GetCloudAccountIdentifier() string {
+	if m != nil {
+		return m.CloudAccountIdentifier
+	}
+	return ""
+}
+
+// WatchProfilesEvent is a change of a profile
+type WatchProfilesEvent struct {
+	// Type of the change
+	Type WatchEventType `protobuf:"varint,1,opt,name=type,proto3,enum=storageserver.v1.WatchEventType" json:"type,omitempty"`
+	// ResourceVersion of the change, to resume watching after it
+	ResourceVersion string `protobuf:"bytes,2,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	// ApplicationProfile is populated when kind is "ApplicationProfile" (metadata only for BOOKMARK events)
+	ApplicationProfile *v1beta1.ApplicationProfile `protobuf:"bytes,3,opt,name=application_profile,json=applicationProfile,proto3" json:"application_profile,omitempty"`
+	// NetworkNeighborhood is populated when kind is "NetworkNeighborhood" (metadata only for BOOKMARK events)
+	NetworkNeighborhood *v1beta1.NetworkNeighborhood `protobuf:"bytes,4,opt,name=network_neighborhood,json=networkNeighborhood,proto3" json:"network_neighborhood,omitempty"`
+	// Error message of ERROR events
+	ErrorMessage string `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Error code of ERROR events; ERROR_CODE_RESOURCE_EXPIRED means that resource_version is too old to resume watching
+	ErrorCode            ErrorCode `protobuf:"varint,6,opt,name=error_code,json=errorCode,proto3,enum=storageserver.v1.ErrorCode" json:"error_code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *WatchProfilesEvent) Reset()         { *m = WatchProfilesEvent{} }
+func (m *WatchProfilesEvent) String() string { return proto.CompactTextString(m) }
+func (*WatchProfilesEvent) ProtoMessage()    {}
+func (*WatchProfilesEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{12}
+}
+func (m *WatchProfilesEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchProfilesEvent.Unmarshal(m, b)
+}
+func (m *WatchProfilesEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchProfilesEvent.Marshal(b, m, deterministic)
+}
+func (m *WatchProfilesEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchProfilesEvent.Merge(m, src)
+}
+func (m *WatchProfilesEvent) XXX_Size() int {
+	return xxx_messageInfo_WatchProfilesEvent.Size(m)
+}
+func (m *WatchProfilesEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchProfilesEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchProfilesEvent proto.InternalMessageInfo
+
+func (m *WatchProfilesEvent) GetType() WatchEventType {
+	if m != nil {
+		return m.Type
+	}
+	return WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED
+}
+
+func (m *WatchProfilesEvent) GetResourceVersion() string {
+	if m != nil {
+		return m.ResourceVersion
+	}
+	return ""
+}
+
+func (m *WatchProfilesEvent) GetApplicationProfile() *v1beta1.ApplicationProfile {
+	if m != nil {
+		return m.ApplicationProfile
+	}
+	return nil
+}
+
+func (m *WatchProfilesEvent) GetNetworkNeighborhood() *v1beta1.NetworkNeighborhood {
+	if m != nil {
+		return m.NetworkNeighborhood
+	}
+	return nil
+}
+
+func (m *WatchProfilesEvent) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+func (m *WatchProfilesEvent) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+// DeleteProfileRequest requests the deletion of a profile
+// customer_guid, cluster, host_type, and host_id are sent via gRPC metadata headers
+type DeleteProfileRequest struct {
+	// Kind specifies the type of profile: "ApplicationProfile", "NetworkNeighborhood", or "ContainerProfile"
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Namespace of the workload (k8s scope identifier)
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Name of the workload
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Region of the resource (non-k8s scope identifier, e.g. "us-east-1")
+	Region string `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	// CloudAccountIdentifier of the resource (non-k8s scope identifier, e.g. AWS account ID "123456789012", GCP project ID)
+	CloudAccountIdentifier string   `protobuf:"bytes,5,opt,name=cloud_account_identifier,json=cloudAccountIdentifier,proto3" json:"cloud_account_identifier,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
+}
+
+func (m *DeleteProfileRequest) Reset()         { *m = DeleteProfileRequest{} }
+func (m *DeleteProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteProfileRequest) ProtoMessage()    {}
+func (*DeleteProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{13}
+}
+func (m *DeleteProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteProfileRequest.Unmarshal(m, b)
+}
+func (m *DeleteProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteProfileRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteProfileRequest.Merge(m, src)
+}
+func (m *DeleteProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteProfileRequest.Size(m)
+}
+func (m *DeleteProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteProfileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteProfileRequest proto.InternalMessageInfo
+
+func (m *DeleteProfileRequest) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *DeleteProfileRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *DeleteProfileRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DeleteProfileRequest) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *DeleteProfileRequest) GetCloudAccountIdentifier() string {
+	if m != nil {
+		return m.CloudAccountIdentifier
+	}
+	return ""
+}
+
+// DeleteProfileResponse indicates success or failure of the operation
+type DeleteProfileResponse struct {
+	// Success indicates if the profile was successfully deleted
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Error message if the operation failed
+	ErrorMessage string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Error code for programmatic error handling
+	ErrorCode            ErrorCode `protobuf:"varint,3,opt,name=error_code,json=errorCode,proto3,enum=storageserver.v1.ErrorCode" json:"error_code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *DeleteProfileResponse) Reset()         { *m = DeleteProfileResponse{} }
+func (m *DeleteProfileResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteProfileResponse) ProtoMessage()    {}
+func (*DeleteProfileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{14}
+}
+func (m *DeleteProfileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteProfileResponse.Unmarshal(m, b)
+}
+func (m *DeleteProfileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteProfileResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteProfileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteProfileResponse.Merge(m, src)
+}
+func (m *DeleteProfileResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteProfileResponse.Size(m)
+}
+func (m *DeleteProfileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteProfileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteProfileResponse proto.InternalMessageInfo
+
+func (m *DeleteProfileResponse) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *DeleteProfileResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+func (m *DeleteProfileResponse) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+// CompleteProfileRequest requests to mark a profile as completed
+// customer_guid, cluster, host_type, and host_id are sent via gRPC metadata headers
+type CompleteProfileRequest struct {
+	// Kind specifies the type of profile: "ApplicationProfile", "NetworkNeighborhood", or "ContainerProfile"
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Namespace of the workload (k8s scope identifier)
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Name of the workload
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Region of the resource (non-k8s scope identifier, e.g. "us-east-1")
+	Region string `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	// CloudAccountIdentifier of the resource (non-k8s scope identifier, e.g. AWS account ID "123456789012", GCP project ID)
+	CloudAccountIdentifier string   `protobuf:"bytes,5,opt,name=cloud_account_identifier,json=cloudAccountIdentifier,proto3" json:"cloud_account_identifier,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
+}
+
+func (m *CompleteProfileRequest) Reset()         { *m = CompleteProfileRequest{} }
+func (m *CompleteProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*CompleteProfileRequest) ProtoMessage()    {}
+func (*CompleteProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{15}
+}
+func (m *CompleteProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteProfileRequest.Unmarshal(m, b)
+}
+func (m *CompleteProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteProfileRequest.Marshal(b, m, deterministic)
+}
+func (m *CompleteProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteProfileRequest.Merge(m, src)
+}
+func (m *CompleteProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_CompleteProfileRequest.Size(m)
+}
+func (m *CompleteProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteProfileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteProfileRequest proto.InternalMessageInfo
+
+func (m *CompleteProfileRequest) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *CompleteProfileRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *CompleteProfileRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CompleteProfileRequest) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *CompleteProfileRequest) GetCloudAccountIdentifier() string {
+	if m != nil {
+		return m.CloudAccountIdentifier
+	}
+	return ""
+}
+
+// CompleteProfileResponse indicates success or failure of the operation
+type CompleteProfileResponse struct {
+	// Success indicates if the profile was successfully marked as completed
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Error message if the operation failed
+	ErrorMessage string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Error code for programmatic error handling
+	ErrorCode            ErrorCode `protobuf:"varint,3,opt,name=error_code,json=errorCode,proto3,enum=storageserver.v1.ErrorCode" json:"error_code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CompleteProfileResponse) Reset()         { *m = CompleteProfileResponse{} }
+func (m *CompleteProfileResponse) String() string { return proto.CompactTextString(m) }
+func (*CompleteProfileResponse) ProtoMessage()    {}
+func (*CompleteProfileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3d90829bc66d9c54, []int{16}
+}
+func (m *CompleteProfileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteProfileResponse.Unmarshal(m, b)
+}
+func (m *CompleteProfileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteProfileResponse.Marshal(b, m, deterministic)
+}
+func (m *CompleteProfileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteProfileResponse.Merge(m, src)
+}
+func (m *CompleteProfileResponse) XXX_Size() int {
+	return xxx_messageInfo_CompleteProfileResponse.Size(m)
+}
+func (m *CompleteProfileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteProfileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteProfileResponse proto.InternalMessageInfo
+
+func (m *CompleteProfileResponse) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *CompleteProfileResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+func (m *CompleteProfileResponse) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
 func init() {
 	proto.RegisterEnum("storageserver.v1.ErrorCode", ErrorCode_name, ErrorCode_value)
+	proto.RegisterEnum("storageserver.v1.WatchEventType", WatchEventType_name, WatchEventType_value)
 	proto.RegisterType((*SendContainerProfileRequest)(nil), "storageserver.v1.SendContainerProfileRequest")
 	proto.RegisterType((*SendContainerProfileResponse)(nil), "storageserver.v1.SendContainerProfileResponse")
 	proto.RegisterType((*GetProfileRequest)(nil), "storageserver.v1.GetProfileRequest")
@@ -642,64 +1365,103 @@ func init() {
 	proto.RegisterType((*ListApplicationProfilesResponse)(nil), "storageserver.v1.ListApplicationProfilesResponse")
 	proto.RegisterType((*ListNetworkNeighborhoodsRequest)(nil), "storageserver.v1.ListNetworkNeighborhoodsRequest")
 	proto.RegisterType((*ListNetworkNeighborhoodsResponse)(nil), "storageserver.v1.ListNetworkNeighborhoodsResponse")
+	proto.RegisterType((*StreamContainerProfilesRequest)(nil), "storageserver.v1.StreamContainerProfilesRequest")
+	proto.RegisterType((*ContainerProfileAck)(nil), "storageserver.v1.ContainerProfileAck")
+	proto.RegisterType((*StreamContainerProfilesResponse)(nil), "storageserver.v1.StreamContainerProfilesResponse")
+	proto.RegisterType((*WatchProfilesRequest)(nil), "storageserver.v1.WatchProfilesRequest")
+	proto.RegisterType((*WatchProfilesEvent)(nil), "storageserver.v1.WatchProfilesEvent")
+	proto.RegisterType((*DeleteProfileRequest)(nil), "storageserver.v1.DeleteProfileRequest")
+	proto.RegisterType((*DeleteProfileResponse)(nil), "storageserver.v1.DeleteProfileResponse")
+	proto.RegisterType((*CompleteProfileRequest)(nil), "storageserver.v1.CompleteProfileRequest")
+	proto.RegisterType((*CompleteProfileResponse)(nil), "storageserver.v1.CompleteProfileResponse")
 }
 
 func init() { proto.RegisterFile("storage_service.proto", fileDescriptor_3d90829bc66d9c54) }
 
 var fileDescriptor_3d90829bc66d9c54 = []byte{
-	// 856 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x56, 0x41, 0x8f, 0xda, 0x46,
-	0x14, 0xae, 0x81, 0x65, 0xcb, 0x4b, 0x1b, 0x39, 0xb3, 0x64, 0xe3, 0xb2, 0xdb, 0x14, 0x91, 0x1e,
-	0x56, 0x95, 0x6a, 0x07, 0x7a, 0xa9, 0x7a, 0xa3, 0xe0, 0x4d, 0x91, 0x08, 0x26, 0x03, 0xa4, 0x52,
-	0x2e, 0x96, 0xb1, 0xdf, 0xb2, 0x23, 0xc0, 0xe3, 0x7a, 0xcc, 0xe6, 0x56, 0xb5, 0x97, 0xa8, 0x55,
-	0x7f, 0x41, 0x6f, 0xbd, 0xf5, 0xd0, 0x7f, 0xd0, 0x1c, 0xfa, 0x67, 0xfa, 0x43, 0x2a, 0xc6, 0x66,
-	0x97, 0xc5, 0x06, 0x25, 0x52, 0xb6, 0xab, 0x9c, 0x98, 0xf9, 0xde, 0x9b, 0x37, 0x1f, 0xef, 0x7d,
-	0xf3, 0xfc, 0xe0, 0xbe, 0x88, 0x78, 0xe8, 0x4c, 0xd0, 0x16, 0x18, 0x5e, 0x30, 0x17, 0xf5, 0x20,
-	0xe4, 0x11, 0x27, 0x6a, 0x02, 0x2f, 0x51, 0x0c, 0xf5, 0x8b, 0x7a, 0xe5, 0xd9, 0x84, 0x45, 0xe7,
-	0x8b, 0xb1, 0xee, 0xf2, 0xb9, 0x31, 0x5d, 0x8c, 0x51, 0xb8, 0x4e, 0x80, 0x46, 0xe2, 0x66, 0x04,
-	0xd3, 0x89, 0xe1, 0x04, 0x4c, 0x18, 0x82, 0x9f, 0x45, 0x2f, 0x9d, 0x10, 0x5d, 0x3e, 0x0f, 0xb8,
-	0x60, 0x11, 0xe3, 0xbe, 0x71, 0x51, 0x1f, 0x63, 0xe4, 0xd4, 0x8d, 0x09, 0xfa, 0x18, 0x3a, 0x11,
-	0x7a, 0xf1, 0x25, 0xb5, 0x3f, 0x14, 0x38, 0x1a, 0xa0, 0xef, 0xb5, 0xb8, 0x1f, 0x39, 0xcc, 0xc7,
-	0xb0, 0x1f, 0xf2, 0x33, 0x36, 0x43, 0x8a, 0x3f, 0x2c, 0x50, 0x44, 0xe4, 0x27, 0x05, 0xee, 0xb9,
-	0x2b, 0x9b, 0x1d, 0xc4, 0x46, 0x4d, 0xa9, 0x2a, 0x27, 0x77, 0x1a, 0x03, 0xfd, 0x8a, 0x8f, 0x7e,
-	0xc9, 0x47, 0x4f, 0xf8, 0xe8, 0xc1, 0x74, 0xa2, 0x2f, 0xf9, 0xe8, 0x19, 0x7c, 0xf4, 0x84, 0x8f,
-	0x9e, 0xba, 0x57, 0x75, 0x37, 0x90, 0xda, 0xef, 0x0a, 0x1c, 0x67, 0x53, 0x14, 0x01, 0xf7, 0x05,
-	0x12, 0x0d, 0xf6, 0xc5, 0xc2, 0x75, 0x51, 0x08, 0x49, 0xec, 0x43, 0xba, 0xda, 0x92, 0x47, 0xf0,
-	0x31, 0x86, 0x21, 0x0f, 0xed, 0x39, 0x0a, 0xe1, 0x4c, 0x50, 0xcb, 0x55, 0x95, 0x93, 0x12, 0xfd,
-	0x48, 0x82, 0x4f, 0x63, 0x8c, 0x7c, 0x03, 0x10, 0x3b, 0xb9, 0xdc, 0x43, 0x2d, 0x5f, 0x55, 0x4e,
-	0xee, 0x36, 0x8e, 0xf4, 0xcd, 0xe4, 0xeb, 0xe6, 0xd2, 0xa7, 0xc5, 0x3d, 0xa4, 0x25, 0x5c, 0x2d,
-	0x6b, 0x7f, 0x29, 0x70, 0xef, 0x09, 0x46, 0x1b, 0x49, 0x23, 0x50, 0x98, 0x32, 0xdf, 0x93, 0x6c,
-	0x4a, 0x54, 0xae, 0xc9, 0x31, 0x94, 0x7c, 0x67, 0x8e, 0x22, 0x70, 0xdc, 0x15, 0x8d, 0x2b, 0x60,
-	0x79, 0x62, 0xb9, 0x91, 0xb7, 0x97, 0xa8, 0x5c, 0x93, 0x43, 0x28, 0x86, 0x38, 0x61, 0xdc, 0xd7,
-	0x0a, 0x12, 0x4d, 0x76, 0xe4, 0x6b, 0xd0, 0xdc, 0x19, 0x5f, 0x78, 0xb6, 0xe3, 0xba, 0x7c, 0xe1,
-	0x47, 0x36, 0xf3, 0xd0, 0x8f, 0xd8, 0x19, 0xc3, 0x50, 0xdb, 0x93, 0x9e, 0x87, 0xd2, 0xde, 0x8c,
-	0xcd, 0x9d, 0x4b, 0x6b, 0xed, 0xcf, 0x02, 0x90, 0x75, 0xb6, 0xb7, 0x9e, 0x3f, 0xf2, 0x4a, 0x81,
-	0x03, 0x27, 0x08, 0x66, 0xcc, 0x75, 0x96, 0xb2, 0xb8, 0x14, 0x58, 0x41, 0x0a, 0x6c, 0xf4, 0x0e,
-	0x04, 0xd6, 0xbc, 0x8a, 0xbe, 0xfa, 0xdf, 0xc4, 0x49, 0x61, 0xe4, 0x57, 0x05, 0xca, 0x3e, 0x46,
-	0x2f, 0x79, 0x38, 0xb5, 0x7d, 0x64, 0x93, 0xf3, 0x31, 0x0f, 0xcf, 0x39, 0xf7, 0x64, 0x46, 0xef,
-	0x34, 0x9e, 0xbf, 0x03, 0x26, 0xbd, 0x38, 0x7c, 0x6f, 0x2d, 0x3a, 0x3d, 0xf0, 0xd3, 0xe0, 0x96,
-	0x37, 0x57, 0xfc, 0x3f, 0xdf, 0xdc, 0xdf, 0x0a, 0x3c, 0xec, 0x32, 0x11, 0xa5, 0xb3, 0x27, 0x56,
-	0x22, 0xbf, 0x26, 0x68, 0x65, 0x53, 0xd0, 0x65, 0xd8, 0x9b, 0xb1, 0x39, 0x8b, 0x64, 0x25, 0xf3,
-	0x34, 0xde, 0x2c, 0x65, 0xbe, 0xbc, 0x2a, 0x91, 0xa9, 0x5c, 0xaf, 0xc9, 0xbc, 0xf8, 0xc6, 0x32,
-	0xdf, 0xdf, 0x29, 0xf3, 0xd7, 0x39, 0xf8, 0x6c, 0x2b, 0xf9, 0xdb, 0xd7, 0xfc, 0x2f, 0x0a, 0x94,
-	0x33, 0x34, 0x2f, 0xb4, 0x42, 0x35, 0x7f, 0x73, 0xa2, 0x3f, 0x48, 0x8b, 0x5e, 0x64, 0xd5, 0xa3,
-	0xf6, 0x5a, 0x89, 0xb3, 0x97, 0x21, 0xd7, 0xf7, 0xa0, 0xf6, 0xff, 0xe4, 0xa0, 0xba, 0x9d, 0xfd,
-	0xed, 0x17, 0xff, 0x37, 0x05, 0xee, 0x67, 0xf5, 0x99, 0x55, 0xf5, 0x6f, 0xaa, 0xd1, 0x94, 0x33,
-	0x1a, 0x4d, 0x66, 0xfd, 0xbf, 0x78, 0x95, 0x83, 0xd2, 0x25, 0x75, 0x52, 0x81, 0x43, 0x93, 0x52,
-	0x8b, 0xda, 0x2d, 0xab, 0x6d, 0xda, 0xa3, 0xde, 0xa0, 0x6f, 0xb6, 0x3a, 0xa7, 0x1d, 0xb3, 0xad,
-	0x7e, 0x40, 0x1e, 0x42, 0x65, 0xcd, 0xd6, 0xe9, 0x3d, 0x6f, 0x76, 0x3b, 0x6d, 0x9b, 0x9a, 0xcf,
-	0x46, 0xe6, 0x60, 0xa8, 0x2a, 0xe4, 0x08, 0x1e, 0x5c, 0x3b, 0xdb, 0x1c, 0x0d, 0xbf, 0xb3, 0x68,
-	0xe7, 0x85, 0xd9, 0x56, 0x73, 0xa4, 0x0a, 0xc7, 0x6b, 0xc6, 0x3e, 0xb5, 0x4e, 0x3b, 0x5d, 0xd3,
-	0x1e, 0x5a, 0x96, 0xdd, 0x6d, 0xd2, 0x27, 0xa6, 0x9a, 0xdf, 0xe2, 0xd1, 0xb2, 0x9e, 0xf6, 0xbb,
-	0xe6, 0xd0, 0x6c, 0xab, 0x85, 0x2d, 0x1e, 0x3d, 0x6b, 0x68, 0x9f, 0x5a, 0xa3, 0x5e, 0x5b, 0xdd,
-	0x23, 0x9f, 0xc2, 0x27, 0xd7, 0x28, 0x0e, 0x4d, 0xda, 0x6b, 0x76, 0x6d, 0x89, 0xa9, 0xc5, 0x0d,
-	0x86, 0xfd, 0x51, 0x77, 0xd0, 0xa4, 0x89, 0x71, 0xbf, 0xf1, 0x6f, 0x1e, 0xee, 0x0e, 0xe2, 0xbc,
-	0x0f, 0xe2, 0xc1, 0x8c, 0x2c, 0xa0, 0x9c, 0x35, 0x89, 0x90, 0x2f, 0xd3, 0xd5, 0xdf, 0x31, 0x54,
-	0x55, 0xf4, 0x37, 0x75, 0x4f, 0xf4, 0xfa, 0x3d, 0xc0, 0xd5, 0x67, 0x9b, 0x3c, 0x4a, 0x9f, 0x4e,
-	0x8d, 0x20, 0x95, 0xcf, 0x77, 0x3b, 0x25, 0x81, 0x7f, 0x84, 0x07, 0x5b, 0x1a, 0x25, 0x79, 0x9c,
-	0x0e, 0xb0, 0xfb, 0x83, 0x50, 0xa9, 0xbf, 0xc5, 0x89, 0xe4, 0xfe, 0x9f, 0x15, 0xd0, 0xb6, 0xbd,
-	0x56, 0xb2, 0x25, 0xde, 0x8e, 0xbe, 0x54, 0x69, 0xbc, 0xcd, 0x91, 0x98, 0xc3, 0xb7, 0x8d, 0x17,
-	0x8f, 0x33, 0xc7, 0xea, 0xb1, 0xe3, 0x4e, 0xd1, 0xf7, 0xe4, 0x58, 0xed, 0xce, 0x18, 0xfa, 0x91,
-	0x71, 0x51, 0x37, 0xe4, 0xd4, 0x3c, 0x2e, 0xca, 0x9f, 0xaf, 0xfe, 0x0b, 0x00, 0x00, 0xff, 0xff,
-	0x8b, 0xc1, 0x4a, 0xbf, 0xba, 0x0b, 0x00, 0x00,
+	// 1336 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x58, 0x41, 0x73, 0xdb, 0x44,
+	0x14, 0x66, 0x6d, 0xc7, 0xa9, 0x5f, 0x49, 0xaa, 0x6e, 0xdc, 0xc4, 0x38, 0xa1, 0xf5, 0xb8, 0x2d,
+	0x4d, 0x3b, 0x83, 0xdc, 0x18, 0x0e, 0xc0, 0xcd, 0xb5, 0xd4, 0xd6, 0x53, 0xc7, 0x72, 0x65, 0x3b,
+	0x85, 0x5e, 0x84, 0x2c, 0x6f, 0x1c, 0x8d, 0x65, 0xad, 0x90, 0xe4, 0x94, 0x5e, 0x98, 0x72, 0x61,
+	0x60, 0xb8, 0x75, 0xb8, 0x70, 0xe3, 0x44, 0x0f, 0xcc, 0x40, 0xaf, 0x0c, 0x07, 0x6e, 0xfc, 0x05,
+	0x7e, 0x04, 0x33, 0xfc, 0x06, 0x46, 0x2b, 0x39, 0xb1, 0x2d, 0xc9, 0x49, 0x66, 0xda, 0xa6, 0x9d,
+	0xe1, 0x64, 0xe9, 0xbd, 0xef, 0xed, 0x7e, 0xfb, 0xfc, 0xbd, 0xd5, 0xdb, 0x85, 0x0b, 0x8e, 0x4b,
+	0x6d, 0xb5, 0x4f, 0x14, 0x87, 0xd8, 0xfb, 0xba, 0x46, 0x78, 0xcb, 0xa6, 0x2e, 0xc5, 0x5c, 0x60,
+	0xf6, 0xac, 0xc4, 0xe6, 0xf7, 0xb7, 0xf2, 0xf7, 0xfb, 0xba, 0xbb, 0x37, 0xea, 0xf2, 0x1a, 0x1d,
+	0x96, 0x06, 0xa3, 0x2e, 0x71, 0x34, 0xd5, 0x22, 0xa5, 0x00, 0x56, 0xb2, 0x06, 0xfd, 0x92, 0x6a,
+	0xe9, 0x4e, 0xc9, 0xa1, 0xbb, 0xee, 0x23, 0xd5, 0x26, 0x1a, 0x1d, 0x5a, 0xd4, 0xd1, 0x5d, 0x9d,
+	0x9a, 0xa5, 0xfd, 0xad, 0x2e, 0x71, 0xd5, 0xad, 0x52, 0x9f, 0x98, 0xc4, 0x56, 0x5d, 0xd2, 0xf3,
+	0x27, 0x29, 0xfe, 0x84, 0x60, 0xbd, 0x45, 0xcc, 0x5e, 0x95, 0x9a, 0xae, 0xaa, 0x9b, 0xc4, 0x6e,
+	0xda, 0x74, 0x57, 0x37, 0x88, 0x4c, 0xbe, 0x18, 0x11, 0xc7, 0xc5, 0x4f, 0x10, 0x9c, 0xd7, 0xc6,
+	0x3e, 0xc5, 0xf2, 0x9d, 0x39, 0x54, 0x40, 0x9b, 0x67, 0xcb, 0x2d, 0xfe, 0x90, 0x0f, 0x7f, 0xc0,
+	0x87, 0x0f, 0xf8, 0xf0, 0xd6, 0xa0, 0xcf, 0x7b, 0x7c, 0xf8, 0x08, 0x3e, 0x7c, 0xc0, 0x87, 0x0f,
+	0xcd, 0xcb, 0x69, 0x33, 0x96, 0xe2, 0x8f, 0x08, 0x36, 0xa2, 0x29, 0x3a, 0x16, 0x35, 0x1d, 0x82,
+	0x73, 0xb0, 0xe8, 0x8c, 0x34, 0x8d, 0x38, 0x0e, 0x23, 0x76, 0x46, 0x1e, 0xbf, 0xe2, 0xcb, 0xb0,
+	0x44, 0x6c, 0x9b, 0xda, 0xca, 0x90, 0x38, 0x8e, 0xda, 0x27, 0xb9, 0x44, 0x01, 0x6d, 0x66, 0xe4,
+	0xb7, 0x99, 0x71, 0xdb, 0xb7, 0xe1, 0x4f, 0x00, 0x7c, 0x90, 0x46, 0x7b, 0x24, 0x97, 0x2c, 0xa0,
+	0xcd, 0xe5, 0xf2, 0x3a, 0x3f, 0x9b, 0x7c, 0x5e, 0xf4, 0x30, 0x55, 0xda, 0x23, 0x72, 0x86, 0x8c,
+	0x1f, 0x8b, 0xbf, 0x20, 0x38, 0x7f, 0x87, 0xb8, 0x33, 0x49, 0xc3, 0x90, 0x1a, 0xe8, 0x66, 0x8f,
+	0xb1, 0xc9, 0xc8, 0xec, 0x19, 0x6f, 0x40, 0xc6, 0x54, 0x87, 0xc4, 0xb1, 0x54, 0x6d, 0x4c, 0xe3,
+	0xd0, 0xe0, 0x45, 0x78, 0x2f, 0x6c, 0xf6, 0x8c, 0xcc, 0x9e, 0xf1, 0x2a, 0xa4, 0x6d, 0xd2, 0xd7,
+	0xa9, 0x99, 0x4b, 0x31, 0x6b, 0xf0, 0x86, 0x3f, 0x82, 0x9c, 0x66, 0xd0, 0x51, 0x4f, 0x51, 0x35,
+	0x8d, 0x8e, 0x4c, 0x57, 0xd1, 0x7b, 0xc4, 0x74, 0xf5, 0x5d, 0x9d, 0xd8, 0xb9, 0x05, 0x86, 0x5c,
+	0x65, 0xfe, 0x8a, 0xef, 0xae, 0x1d, 0x78, 0x8b, 0xcf, 0x52, 0x80, 0x27, 0xd9, 0x9e, 0x7a, 0xfe,
+	0xf0, 0x37, 0x08, 0x56, 0x54, 0xcb, 0x32, 0x74, 0x4d, 0xf5, 0x64, 0x71, 0x20, 0xb0, 0x14, 0x13,
+	0x58, 0xe7, 0x05, 0x08, 0xac, 0x72, 0x38, 0xfa, 0x78, 0xdd, 0x58, 0x0d, 0xd9, 0xf0, 0x77, 0x08,
+	0xb2, 0x26, 0x71, 0x1f, 0x51, 0x7b, 0xa0, 0x98, 0x44, 0xef, 0xef, 0x75, 0xa9, 0xbd, 0x47, 0x69,
+	0x8f, 0x65, 0xf4, 0x6c, 0x79, 0xe7, 0x05, 0x30, 0x69, 0xf8, 0xc3, 0x37, 0x26, 0x46, 0x97, 0x57,
+	0xcc, 0xb0, 0x31, 0xa6, 0xe6, 0xd2, 0xaf, 0xb2, 0xe6, 0x7e, 0x4b, 0xc0, 0xc5, 0xba, 0xee, 0xb8,
+	0xe1, 0xec, 0x39, 0x63, 0x91, 0x4f, 0x09, 0x1a, 0xcd, 0x0a, 0x3a, 0x0b, 0x0b, 0x86, 0x3e, 0xd4,
+	0x5d, 0xf6, 0x4f, 0x26, 0x65, 0xff, 0xc5, 0x93, 0xb9, 0x37, 0x55, 0x20, 0x53, 0xf6, 0x3c, 0x21,
+	0xf3, 0xf4, 0xb1, 0x65, 0xbe, 0x38, 0x4f, 0xe6, 0xf8, 0x2a, 0x2c, 0x1b, 0x6a, 0x97, 0x18, 0x8a,
+	0x43, 0x0c, 0xa2, 0xb9, 0xd4, 0xce, 0x9d, 0x61, 0xf8, 0x25, 0x66, 0x6d, 0x05, 0x46, 0x0f, 0xb6,
+	0xab, 0x13, 0xa3, 0x77, 0x08, 0xcb, 0xf8, 0x30, 0x66, 0x9d, 0x84, 0xa9, 0x86, 0xa1, 0x1c, 0x2c,
+	0xcd, 0xc9, 0x01, 0x2b, 0x92, 0x25, 0xd5, 0x30, 0x1a, 0x07, 0xc6, 0xe2, 0x1f, 0x09, 0xb8, 0x14,
+	0x9b, 0xb1, 0xd3, 0x2f, 0xb4, 0x6f, 0x11, 0x64, 0x23, 0x0a, 0xcd, 0xc9, 0xa5, 0x0a, 0xc9, 0x97,
+	0x57, 0x69, 0x2b, 0xe1, 0x4a, 0x73, 0xa2, 0x44, 0x50, 0x7c, 0x1e, 0x64, 0x2f, 0xa2, 0x46, 0xfe,
+	0x17, 0x5c, 0xb4, 0xe0, 0xfe, 0x4c, 0x40, 0x21, 0x3e, 0x65, 0xa7, 0xaf, 0xb8, 0xef, 0x11, 0x5c,
+	0x88, 0xda, 0x51, 0xc7, 0x92, 0x7b, 0x59, 0x5b, 0x6a, 0x36, 0x62, 0x4b, 0x8d, 0x16, 0xdd, 0xef,
+	0x08, 0x2e, 0xb6, 0x5c, 0x9b, 0xa8, 0xc3, 0xd9, 0x1d, 0xd1, 0x79, 0x7d, 0xda, 0x1f, 0x4f, 0xd8,
+	0xba, 0xd9, 0x23, 0x5f, 0xb2, 0x3f, 0x28, 0x29, 0xfb, 0x2f, 0xc5, 0x9f, 0x11, 0xac, 0xcc, 0x06,
+	0x57, 0xb4, 0xc1, 0x21, 0x1a, 0x4d, 0xa0, 0x27, 0x65, 0x90, 0x38, 0x42, 0x06, 0xc9, 0x23, 0x65,
+	0x90, 0x3a, 0x51, 0x87, 0xf4, 0x37, 0x82, 0x4b, 0xb1, 0x49, 0x3e, 0x7d, 0x95, 0x7e, 0x0c, 0x29,
+	0x55, 0x1b, 0x8c, 0x35, 0x79, 0x35, 0x1c, 0x15, 0x91, 0x64, 0x99, 0x85, 0x14, 0xff, 0x45, 0x90,
+	0x7d, 0xa0, 0xba, 0xda, 0xde, 0xac, 0x68, 0x4e, 0xde, 0xfe, 0x5d, 0x07, 0xce, 0x26, 0x0e, 0x1d,
+	0xd9, 0x1a, 0x51, 0xf6, 0x89, 0xed, 0x78, 0x9b, 0x93, 0xff, 0x47, 0x9c, 0x1b, 0xdb, 0x77, 0x7c,
+	0x33, 0xbe, 0x06, 0xe7, 0x54, 0xc3, 0xa0, 0x8f, 0x94, 0x2e, 0xa5, 0x83, 0xa1, 0x6a, 0x33, 0xee,
+	0x5e, 0xce, 0x96, 0x99, 0xf9, 0xd6, 0xd8, 0x3a, 0xb1, 0xcd, 0x2d, 0x1c, 0x7b, 0x9b, 0x4b, 0xcf,
+	0x6d, 0x1f, 0xff, 0x49, 0x02, 0x9e, 0x5a, 0xb0, 0xb8, 0x4f, 0x4c, 0x17, 0x7f, 0x08, 0x29, 0xf7,
+	0xb1, 0xe5, 0x57, 0xc5, 0x72, 0xb9, 0x10, 0x4e, 0x21, 0x8b, 0x61, 0xd8, 0xf6, 0x63, 0x8b, 0xc8,
+	0x0c, 0x1d, 0xb9, 0xe4, 0x44, 0xf4, 0x92, 0xe3, 0x9a, 0xc4, 0xe4, 0x6b, 0xd3, 0x24, 0xa6, 0x5e,
+	0x7d, 0x93, 0x18, 0xaa, 0x8c, 0x85, 0x23, 0x2b, 0x23, 0x7d, 0xa2, 0xc2, 0xfd, 0x15, 0x41, 0x56,
+	0x20, 0x06, 0x71, 0xc9, 0x1b, 0x72, 0xba, 0x79, 0x8a, 0xe0, 0xc2, 0x0c, 0xe1, 0xd3, 0x3f, 0x20,
+	0x3e, 0x47, 0xb0, 0x5a, 0xa5, 0x43, 0xeb, 0x0d, 0xca, 0xe3, 0x0f, 0x08, 0xd6, 0x42, 0x94, 0x4f,
+	0x3d, 0x93, 0x37, 0x9e, 0x25, 0x20, 0x73, 0xe0, 0xc0, 0x79, 0x58, 0x15, 0x65, 0x59, 0x92, 0x95,
+	0xaa, 0x24, 0x88, 0x4a, 0xa7, 0xd1, 0x6a, 0x8a, 0xd5, 0xda, 0xed, 0x9a, 0x28, 0x70, 0x6f, 0xe1,
+	0x8b, 0x90, 0x9f, 0xf0, 0xd5, 0x1a, 0x3b, 0x95, 0x7a, 0x4d, 0x50, 0x64, 0xf1, 0x7e, 0x47, 0x6c,
+	0xb5, 0x39, 0x84, 0xd7, 0x61, 0x6d, 0x2a, 0xb6, 0xd2, 0x69, 0xdf, 0x95, 0xe4, 0xda, 0x43, 0x51,
+	0xe0, 0x12, 0xb8, 0x00, 0x1b, 0x13, 0xce, 0xa6, 0x2c, 0xdd, 0xae, 0xd5, 0x45, 0xa5, 0x2d, 0x49,
+	0x4a, 0xbd, 0x22, 0xdf, 0x11, 0xb9, 0x64, 0x0c, 0xa2, 0x2a, 0x6d, 0x37, 0xeb, 0x62, 0x5b, 0x14,
+	0xb8, 0x54, 0x0c, 0xa2, 0x21, 0xb5, 0x95, 0xdb, 0x52, 0xa7, 0x21, 0x70, 0x0b, 0xf8, 0x5d, 0x78,
+	0x67, 0x8a, 0x62, 0x5b, 0x94, 0x1b, 0x95, 0xba, 0xc2, 0x6c, 0x5c, 0x7a, 0x86, 0x61, 0xb3, 0x53,
+	0x6f, 0x55, 0xe4, 0xc0, 0xb9, 0x88, 0x2f, 0xc1, 0xfa, 0x84, 0x53, 0x16, 0x5b, 0x52, 0x47, 0xae,
+	0x8a, 0x8a, 0xf8, 0x69, 0xb3, 0x26, 0x8b, 0x02, 0x77, 0xe6, 0xc6, 0x5f, 0x08, 0x96, 0xa7, 0xf7,
+	0x5c, 0x8f, 0xd1, 0x83, 0x4a, 0xbb, 0x7a, 0x57, 0x11, 0x77, 0xc4, 0x46, 0x5b, 0x69, 0x7f, 0xd6,
+	0x9c, 0x4d, 0x5a, 0x1e, 0x56, 0x43, 0x88, 0x8a, 0x20, 0x88, 0x02, 0x87, 0x3c, 0xb6, 0x21, 0xdf,
+	0xb6, 0x24, 0xf8, 0xa1, 0x09, 0xbc, 0x01, 0xb9, 0x90, 0x5b, 0x10, 0xfd, 0x64, 0x24, 0x23, 0x83,
+	0x6f, 0x49, 0xd2, 0xbd, 0xed, 0x8a, 0x7c, 0x8f, 0x4b, 0x45, 0xce, 0xeb, 0xaf, 0x74, 0xa1, 0xfc,
+	0x74, 0x11, 0x96, 0x5b, 0xbe, 0x38, 0x5a, 0xfe, 0xd5, 0x18, 0x1e, 0x41, 0x36, 0xea, 0x2e, 0x08,
+	0xbf, 0x1f, 0x56, 0xd1, 0x9c, 0x6b, 0xad, 0x3c, 0x7f, 0x5c, 0x78, 0xa0, 0xfb, 0x07, 0x00, 0x87,
+	0x17, 0x27, 0xf8, 0x72, 0x38, 0x3a, 0x74, 0x09, 0x94, 0xbf, 0x32, 0x1f, 0x14, 0x0c, 0xfc, 0x15,
+	0xac, 0xc5, 0x9c, 0x1a, 0xf1, 0xcd, 0xf0, 0x00, 0xf3, 0x8f, 0xe4, 0xf9, 0xad, 0x13, 0x44, 0x04,
+	0xf3, 0x7f, 0x8d, 0x20, 0x17, 0x77, 0x8a, 0xc0, 0x31, 0xe3, 0xcd, 0x39, 0xa4, 0xe5, 0xcb, 0x27,
+	0x09, 0x09, 0x38, 0x3c, 0x41, 0xb0, 0x16, 0xd3, 0x22, 0x46, 0x25, 0x61, 0x7e, 0xcb, 0x9e, 0xdf,
+	0x3a, 0x41, 0x84, 0x4f, 0x60, 0x13, 0x61, 0x05, 0x96, 0xa6, 0x3a, 0x1b, 0xfc, 0x5e, 0x4c, 0x1b,
+	0x33, 0x3b, 0xdb, 0x95, 0x23, 0x70, 0xac, 0x04, 0x6f, 0x22, 0xfc, 0x39, 0x2c, 0x4d, 0x7d, 0x9b,
+	0xa2, 0x26, 0x88, 0xfa, 0xda, 0xe6, 0xaf, 0x1d, 0x89, 0x0b, 0xb2, 0xb8, 0x0b, 0xe7, 0x66, 0x76,
+	0x6d, 0xbc, 0x19, 0xd5, 0xce, 0x46, 0x7d, 0x8b, 0xf2, 0xd7, 0x8f, 0x81, 0xf4, 0xe7, 0xb9, 0x55,
+	0x7e, 0x78, 0x33, 0xf2, 0x1a, 0xba, 0xab, 0x6a, 0x03, 0x62, 0xf6, 0xd8, 0x35, 0xb4, 0x66, 0xe8,
+	0xc4, 0x74, 0x4b, 0xfb, 0x5b, 0x25, 0x76, 0xcb, 0xdc, 0x4d, 0xb3, 0x9f, 0x0f, 0xfe, 0x1b, 0x00,
+	0x13, 0x0c, 0xcf, 0xca, 0xea, 0x16, 0x00, 0x00,
 }
@@ -23,6 +23,10 @@ const (
 	StorageService_GetProfile_FullMethodName               = "/storageserver.v1.StorageService/GetProfile"
 	StorageService_ListApplicationProfiles_FullMethodName  = "/storageserver.v1.StorageService/ListApplicationProfiles"
 	StorageService_ListNetworkNeighborhoods_FullMethodName = "/storageserver.v1.StorageService/ListNetworkNeighborhoods"
+	StorageService_StreamContainerProfiles_FullMethodName  = "/storageserver.v1.StorageService/StreamContainerProfiles"
+	StorageService_WatchProfiles_FullMethodName            = "/storageserver.v1.StorageService/WatchProfiles"
+	StorageService_DeleteProfile_FullMethodName            = "/storageserver.v1.StorageService/DeleteProfile"
+	StorageService_CompleteProfile_FullMethodName          = "/storageserver.v1.StorageService/CompleteProfile"
 )
 
 // StorageServiceClient is the client API for StorageService service.
@@ -37,10 +41,25 @@ type StorageServiceClient interface {
 	// GetProfile retrieves an aggregated profile (ApplicationProfile or NetworkNeighborhood)
 	// by fetching container profiles from S3 and aggregating them
 	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*GetProfileResponse, error)
-	// ListApplicationProfiles lists all ApplicationProfiles in a namespace (returns metadata only, nil Spec)
+	// ListApplicationProfiles lists all ApplicationProfiles in a namespace, or in all namespaces,
+	// optionally filtered by label and field selectors (returns metadata only, nil Spec)
 	ListApplicationProfiles(ctx context.Context, in *ListApplicationProfilesRequest, opts ...grpc.CallOption) (*ListApplicationProfilesResponse, error)
-	// ListNetworkNeighborhoods lists all NetworkNeighborhoods in a namespace (returns metadata only, nil Spec)
+	// ListNetworkNeighborhoods lists all NetworkNeighborhoods in a namespace, or in all namespaces,
+	// optionally filtered by label and field selectors (returns metadata only, nil Spec)
 	ListNetworkNeighborhoods(ctx context.Context, in *ListNetworkNeighborhoodsRequest, opts ...grpc.CallOption) (*ListNetworkNeighborhoodsResponse, error)
+	// StreamContainerProfiles receives a stream of container profiles from node agent
+	// and sends them to Pulsar, acknowledging each profile once the stream is closed.
+	// A profile which fails does not interrupt the stream.
+	StreamContainerProfiles(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamContainerProfilesRequest, StreamContainerProfilesResponse], error)
+	// WatchProfiles streams the changes of ApplicationProfiles or NetworkNeighborhoods in a namespace,
+	// following the Kubernetes watch model
+	WatchProfiles(ctx context.Context, in *WatchProfilesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchProfilesEvent], error)
+	// DeleteProfile deletes a profile (ApplicationProfile, NetworkNeighborhood, or ContainerProfile),
+	// so that its behavior is learned again
+	DeleteProfile(ctx context.Context, in *DeleteProfileRequest, opts ...grpc.CallOption) (*DeleteProfileResponse, error)
+	// CompleteProfile marks a profile (ApplicationProfile, NetworkNeighborhood, or ContainerProfile) as completed,
+	// ending its learning period
+	CompleteProfile(ctx context.Context, in *CompleteProfileRequest, opts ...grpc.CallOption) (*CompleteProfileResponse, error)
 }
 
 type storageServiceClient struct {
@@ -91,6 +110,58 @@ func (c *storageServiceClient) ListNetworkNeighborhoods(ctx context.Context, in
 	return out, nil
 }
 
+func (c *storageServiceClient) StreamContainerProfiles(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamContainerProfilesRequest, StreamContainerProfilesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StorageService_ServiceDesc.Streams[0], StorageService_StreamContainerProfiles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamContainerProfilesRequest, StreamContainerProfilesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageService_StreamContainerProfilesClient = grpc.ClientStreamingClient[StreamContainerProfilesRequest, StreamContainerProfilesResponse]
+
+func (c *storageServiceClient) WatchProfiles(ctx context.Context, in *WatchProfilesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchProfilesEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StorageService_ServiceDesc.Streams[1], StorageService_WatchProfiles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchProfilesRequest, WatchProfilesEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageService_WatchProfilesClient = grpc.ServerStreamingClient[WatchProfilesEvent]
+
+func (c *storageServiceClient) DeleteProfile(ctx context.Context, in *DeleteProfileRequest, opts ...grpc.CallOption) (*DeleteProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteProfileResponse)
+	err := c.cc.Invoke(ctx, StorageService_DeleteProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageServiceClient) CompleteProfile(ctx context.Context, in *CompleteProfileRequest, opts ...grpc.CallOption) (*CompleteProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompleteProfileResponse)
+	err := c.cc.Invoke(ctx, StorageService_CompleteProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StorageServiceServer is the server API for StorageService service.
 // All implementations must embed UnimplementedStorageServiceServer
 // for forward compatibility.
@@ -103,10 +174,25 @@ type StorageServiceServer interface {
 	// GetProfile retrieves an aggregated profile (ApplicationProfile or NetworkNeighborhood)
 	// by fetching container profiles from S3 and aggregating them
 	GetProfile(context.Context, *GetProfileRequest) (*GetProfileResponse, error)
-	// ListApplicationProfiles lists all ApplicationProfiles in a namespace (returns metadata only, nil Spec)
+	// ListApplicationProfiles lists all ApplicationProfiles in a namespace, or in all namespaces,
+	// optionally filtered by label and field selectors (returns metadata only, nil Spec)
 	ListApplicationProfiles(context.Context, *ListApplicationProfilesRequest) (*ListApplicationProfilesResponse, error)
-	// ListNetworkNeighborhoods lists all NetworkNeighborhoods in a namespace (returns metadata only, nil Spec)
+	// ListNetworkNeighborhoods lists all NetworkNeighborhoods in a namespace, or in all namespaces,
+	// optionally filtered by label and field selectors (returns metadata only, nil Spec)
 	ListNetworkNeighborhoods(context.Context, *ListNetworkNeighborhoodsRequest) (*ListNetworkNeighborhoodsResponse, error)
+	// StreamContainerProfiles receives a stream of container profiles from node agent
+	// and sends them to Pulsar, acknowledging each profile once the stream is closed.
+	// A profile which fails does not interrupt the stream.
+	StreamContainerProfiles(grpc.ClientStreamingServer[StreamContainerProfilesRequest, StreamContainerProfilesResponse]) error
+	// WatchProfiles streams the changes of ApplicationProfiles or NetworkNeighborhoods in a namespace,
+	// following the Kubernetes watch model
+	WatchProfiles(*WatchProfilesRequest, grpc.ServerStreamingServer[WatchProfilesEvent]) error
+	// DeleteProfile deletes a profile (ApplicationProfile, NetworkNeighborhood, or ContainerProfile),
+	// so that its behavior is learned again
+	DeleteProfile(context.Context, *DeleteProfileRequest) (*DeleteProfileResponse, error)
+	// CompleteProfile marks a profile (ApplicationProfile, NetworkNeighborhood, or ContainerProfile) as completed,
+	// ending its learning period
+	CompleteProfile(context.Context, *CompleteProfileRequest) (*CompleteProfileResponse, error)
 	mustEmbedUnimplementedStorageServiceServer()
 }
 
@@ -129,6 +215,18 @@ func (UnimplementedStorageServiceServer) ListApplicationProfiles(context.Context
 func (UnimplementedStorageServiceServer) ListNetworkNeighborhoods(context.Context, *ListNetworkNeighborhoodsRequest) (*ListNetworkNeighborhoodsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListNetworkNeighborhoods not implemented")
 }
+func (UnimplementedStorageServiceServer) StreamContainerProfiles(grpc.ClientStreamingServer[StreamContainerProfilesRequest, StreamContainerProfilesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamContainerProfiles not implemented")
+}
+func (UnimplementedStorageServiceServer) WatchProfiles(*WatchProfilesRequest, grpc.ServerStreamingServer[WatchProfilesEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchProfiles not implemented")
+}
+func (UnimplementedStorageServiceServer) DeleteProfile(context.Context, *DeleteProfileRequest) (*DeleteProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProfile not implemented")
+}
+func (UnimplementedStorageServiceServer) CompleteProfile(context.Context, *CompleteProfileRequest) (*CompleteProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteProfile not implemented")
+}
 func (UnimplementedStorageServiceServer) mustEmbedUnimplementedStorageServiceServer() {}
 func (UnimplementedStorageServiceServer) testEmbeddedByValue()                        {}
 
@@ -222,6 +320,60 @@ func _StorageService_ListNetworkNeighborhoods_Handler(srv interface{}, ctx conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StorageService_StreamContainerProfiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StorageServiceServer).StreamContainerProfiles(&grpc.GenericServerStream[StreamContainerProfilesRequest, StreamContainerProfilesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageService_StreamContainerProfilesServer = grpc.ClientStreamingServer[StreamContainerProfilesRequest, StreamContainerProfilesResponse]
+
+func _StorageService_WatchProfiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProfilesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServiceServer).WatchProfiles(m, &grpc.GenericServerStream[WatchProfilesRequest, WatchProfilesEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageService_WatchProfilesServer = grpc.ServerStreamingServer[WatchProfilesEvent]
+
+func _StorageService_DeleteProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServiceServer).DeleteProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageService_DeleteProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServiceServer).DeleteProfile(ctx, req.(*DeleteProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageService_CompleteProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServiceServer).CompleteProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageService_CompleteProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServiceServer).CompleteProfile(ctx, req.(*CompleteProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StorageService_ServiceDesc is the grpc.ServiceDesc for StorageService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -245,7 +397,26 @@ var StorageService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListNetworkNeighborhoods",
 			Handler:    _StorageService_ListNetworkNeighborhoods_Handler,
 		},
+		{
+			MethodName: "DeleteProfile",
+			Handler:    _StorageService_DeleteProfile_Handler,
+		},
+		{
+			MethodName: "CompleteProfile",
+			Handler:    _StorageService_CompleteProfile_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamContainerProfiles",
+			Handler:       _StorageService_StreamContainerProfiles_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchProfiles",
+			Handler:       _StorageService_WatchProfiles_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "storage_service.proto",
 }
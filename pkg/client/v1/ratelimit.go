@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// RouteAPI is the path prefix of all routes served by the API server.
+	RouteAPI = "/api/"
+
+	// RouteReport is the path prefix of all routes served by the report receiver.
+	RouteReport = "/k8s/"
+)
+
+const (
+	// minAdaptiveRate is the lowest fraction of the configured rate to which a limit may be lowered after throttling.
+	minAdaptiveRate = 0.1
+
+	// recoveryRate is the fraction of the configured rate restored after each successful response.
+	recoveryRate = 0.05
+)
+
+// RateLimit configures client-side throttling of requests.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained rate of requests. A value <= 0 means no rate limit.
+	RequestsPerSecond float64
+
+	// Burst is the maximum number of requests sent at once, before the rate limit kicks in.
+	// Defaults to 1.
+	Burst int
+
+	// MaxInFlight is the maximum number of concurrent requests, including the time spent reading responses.
+	// A value <= 0 means no limit.
+	MaxInFlight int
+}
+
+// routeLimiter throttles requests to the routes under a path prefix.
+//
+// Whenever the server responds 429 Too Many Requests, the rate is halved (down to a floor) and requests
+// are held until the delay advertised by the Retry-After header has elapsed. The configured rate is
+// progressively restored on successful responses.
+type routeLimiter struct {
+	prefix   string
+	config   RateLimit
+	limiter  *rate.Limiter
+	inFlight chan struct{}
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+func newRouteLimiter(prefix string, config RateLimit) *routeLimiter {
+	l := &routeLimiter{
+		prefix: prefix,
+		config: config,
+	}
+
+	if config.RequestsPerSecond > 0 {
+		l.limiter = rate.NewLimiter(rate.Limit(config.RequestsPerSecond), max(config.Burst, 1))
+	}
+
+	if config.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, config.MaxInFlight)
+	}
+
+	return l
+}
+
+// acquire waits until a request may be sent. The returned function must be called once the request is complete.
+func (l *routeLimiter) acquire(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	pause := time.Until(l.pausedUntil)
+	l.mu.Unlock()
+
+	if pause > 0 {
+		if err := sleepWithContext(ctx, pause); err != nil {
+			return nil, err
+		}
+	}
+
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-l.inFlight })
+		}
+	}
+
+	if l.limiter != nil {
+		if err := l.limiter.Wait(ctx); err != nil {
+			release()
+
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// observe adapts the limit to the response of the server.
+func (l *routeLimiter) observe(resp *http.Response) {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if delay, ok := retryAfter(resp); ok {
+			l.mu.Lock()
+			if until := time.Now().Add(delay); until.After(l.pausedUntil) {
+				l.pausedUntil = until
+			}
+			l.mu.Unlock()
+		}
+
+		if l.limiter != nil {
+			floor := rate.Limit(l.config.RequestsPerSecond * minAdaptiveRate)
+			l.limiter.SetLimit(max(l.limiter.Limit()/2, floor))
+		}
+
+		return
+	}
+
+	if l.limiter != nil && resp.StatusCode < 400 {
+		configured := rate.Limit(l.config.RequestsPerSecond)
+		if current := l.limiter.Limit(); current < configured {
+			l.limiter.SetLimit(min(current+configured*recoveryRate, configured))
+		}
+	}
+}
+
+// rateLimiterFor selects the limiter with the longest prefix matching the path of a request, if any.
+func (o *KsCloudOptions) rateLimiterFor(req *http.Request) *routeLimiter {
+	var selected *routeLimiter
+	for _, l := range o.rateLimiters {
+		if strings.HasPrefix(req.URL.Path, l.prefix) && (selected == nil || len(l.prefix) > len(selected.prefix)) {
+			selected = l
+		}
+	}
+
+	return selected
+}
+
+// releasingBody releases a rate limiter slot when the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+
+	return b.ReadCloser.Close()
+}
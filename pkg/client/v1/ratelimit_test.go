@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// closeRecorder is a request body recording whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed.Store(true)
+
+	return nil
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should cap the number of requests in flight", func(t *testing.T) {
+		t.Parallel()
+
+		const maxInFlight = 2
+		var current, peak atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := current.Add(1)
+			defer current.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			echoBody(w, r)
+		}))
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "",
+			WithRateLimit(RouteAPI, RateLimit{MaxInFlight: maxInFlight}),
+		)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				_, err := ks.GetAttackTracks()
+				require.Error(t, err) // the echo server does not return valid JSON
+			}()
+		}
+		wg.Wait()
+
+		require.LessOrEqual(t, peak.Load(), int32(maxInFlight))
+		require.Positive(t, peak.Load())
+	})
+
+	t.Run("should throttle requests", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 0, http.StatusOK, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "",
+			WithRateLimit("", RateLimit{RequestsPerSecond: 50, Burst: 1}),
+		)
+		require.NoError(t, err)
+
+		start := time.Now()
+		for range 6 {
+			rdr, _, err := ks.get(context.Background(), srv.URL+pathTestGet)
+			require.NoError(t, err)
+			_ = rdr.Close()
+		}
+
+		// the first request goes through, the next 5 wait for 20ms each
+		require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+		require.Equal(t, int32(6), calls.Load())
+	})
+
+	t.Run("should select the limiter with the longest matching prefix", func(t *testing.T) {
+		t.Parallel()
+
+		ks, err := NewKSCloudAPI("api.example.com", "report.example.com", "account", "",
+			WithRateLimit("", RateLimit{MaxInFlight: 1}),
+			WithRateLimit(RouteAPI, RateLimit{MaxInFlight: 2}),
+			WithRateLimit(RouteAPI, RateLimit{MaxInFlight: 3}),
+		)
+		require.NoError(t, err)
+		require.Len(t, ks.rateLimiters, 2)
+
+		req, err := http.NewRequest(http.MethodGet, ks.getListFrameworkURL(ks.current()), nil)
+		require.NoError(t, err)
+		require.Equal(t, 3, ks.rateLimiterFor(req).config.MaxInFlight)
+
+		req, err = http.NewRequest(http.MethodPost, ks.postReportURL(ks.current(), "cluster", "report"), nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, ks.rateLimiterFor(req).config.MaxInFlight)
+	})
+
+	t.Run("should adapt the rate to throttling", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}})
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "",
+			WithRateLimit("", RateLimit{RequestsPerSecond: 100, Burst: 10}),
+			WithRetryPolicy(fastRetryPolicy()),
+		)
+		require.NoError(t, err)
+
+		limiter := ks.rateLimiters[0]
+
+		start := time.Now()
+		rdr, _, err := ks.get(context.Background(), srv.URL+pathTestGet)
+		require.NoError(t, err)
+		_ = rdr.Close()
+
+		// the retry is held by the limiter until the Retry-After delay has elapsed
+		require.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+		require.Equal(t, int32(2), calls.Load())
+
+		// halved after throttling, then partially restored by the successful retry
+		require.InDelta(t, 55, float64(limiter.limiter.Limit()), 0.001)
+
+		for range 20 {
+			limiter.observe(&http.Response{StatusCode: http.StatusOK})
+		}
+		require.Equal(t, rate.Limit(100), limiter.limiter.Limit())
+
+		for range 10 {
+			limiter.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+		}
+		require.Equal(t, rate.Limit(10), limiter.limiter.Limit())
+	})
+
+	t.Run("should give up waiting when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := newRouteLimiter(RouteAPI, RateLimit{MaxInFlight: 1})
+		release, err := limiter.acquire(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = limiter.acquire(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		release()
+		release() // releasing twice is harmless
+
+		release, err = limiter.acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("should close the request body when giving up waiting", func(t *testing.T) {
+		t.Parallel()
+
+		ks, err := NewKSCloudAPI("api.example.com", "report.example.com", "account", "",
+			WithRateLimit("", RateLimit{MaxInFlight: 1}),
+		)
+		require.NoError(t, err)
+
+		release, err := ks.rateLimiters[0].acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		body := &closeRecorder{Reader: strings.NewReader("{}")}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, ks.postReportURL(ks.current(), "cluster", "report"), body)
+		require.NoError(t, err)
+
+		_, err = ks.throttledRoundTrip(req, ks.defaultRequestOptions(ctx, nil))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.True(t, body.closed.Load())
+	})
+}
@@ -0,0 +1,262 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/armosec/armoapi-go/apis"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is a content encoding applied to request bodies.
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+// compress encodes a request body.
+func (c Compression) compress(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, err := c.newWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(body); err != nil {
+		_ = w.Close()
+
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// newWriter builds a compressing writer.
+func (c Compression) newWriter(w io.Writer) (io.WriteCloser, error) {
+	switch c {
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported compression: %q", c)
+	}
+}
+
+// encodeJSONStream returns a reader streaming the JSON encoding of a document, compressed on the fly if required.
+//
+// Encoding runs in a goroutine that terminates when the document is fully consumed, or when the reader is closed.
+func encodeJSONStream(doc any, compression Compression) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		var (
+			w  io.Writer = pw
+			cw io.WriteCloser
+		)
+
+		if compression != CompressionNone {
+			var err error
+			if cw, err = compression.newWriter(pw); err != nil {
+				pw.CloseWithError(err)
+
+				return
+			}
+			w = cw
+		}
+
+		err := writeJSON(w, doc)
+		if cw != nil {
+			if closeErr := cw.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// writeJSON writes the JSON encoding of a document.
+//
+// Posture reports are encoded one resource and one result at a time, so that only the encoding
+// of a single item is held in memory. Other documents are encoded at once.
+func writeJSON(w io.Writer, doc any) error {
+	switch doc := doc.(type) {
+	case *PostureReport:
+		return writePostureReport(w, doc, doc.Resources, doc.Results)
+	case *reportChunk:
+		return writePostureReport(w, doc.envelope, doc.resources, doc.results)
+	default:
+		return json.NewEncoder(w).Encode(doc)
+	}
+}
+
+// reportChunk is a chunk of a posture report, whose resources and results are already encoded.
+type reportChunk struct {
+	envelope  *PostureReport // the resources and results of the envelope are ignored
+	resources []json.RawMessage
+	results   []json.RawMessage
+}
+
+// writePostureReport writes the JSON encoding of a posture report: the envelope first, then each resource and each result.
+func writePostureReport[R, S any](w io.Writer, report *PostureReport, resources []R, results []S) error {
+	envelope := *report
+	envelope.Resources = nil
+	envelope.Results = nil
+
+	head, err := json.Marshal(&envelope)
+	if err != nil {
+		return err
+	}
+
+	// errors are sticky on a bufio.Writer, and reported by Flush
+	bw := bufio.NewWriter(w)
+
+	// the envelope is left open, to append the resources and the results
+	_, _ = bw.Write(bytes.TrimSuffix(head, []byte("}")))
+
+	if err := writeJSONArray(bw, "resources", resources); err != nil {
+		return err
+	}
+
+	if err := writeJSONArray(bw, "results", results); err != nil {
+		return err
+	}
+
+	_, _ = bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+// writeJSONArray writes an object field holding an array, encoding one item at a time.
+//
+// Items which are already encoded are written as is. Empty arrays are omitted, as the fields of the report are.
+func writeJSONArray[T any](w *bufio.Writer, name string, items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	_, _ = w.WriteString(`,"` + name + `":[`)
+
+	enc := json.NewEncoder(w)
+	for i := range items {
+		if i > 0 {
+			_ = w.WriteByte(',')
+		}
+
+		if raw, ok := any(items[i]).(json.RawMessage); ok {
+			_, _ = w.Write(raw)
+
+			continue
+		}
+
+		if err := enc.Encode(&items[i]); err != nil {
+			return err
+		}
+	}
+
+	return w.WriteByte(']')
+}
+
+// submitReportInChunks splits the resources and results of a posture report across several requests,
+// so that each chunk remains under maxChunkSize bytes (before compression).
+//
+// All chunks share the same report GUID. Chunks are numbered in sequence and the last one is flagged as such.
+// A single resource or result larger than maxChunkSize is sent in a chunk of its own.
+//
+// It returns the response to the last chunk.
+func (api *KSCloudAPI) submitReportInChunks(ctx context.Context, settings ksCloudSettings, report *PostureReport, maxChunkSize int, opts ...RequestOption) (string, error) {
+	envelope := *report
+	chunk := &reportChunk{envelope: &envelope}
+
+	var (
+		chunkNumber int
+		size        int
+	)
+
+	send := func(isLast bool) (string, error) {
+		envelope.PaginationInfo = apis.PaginationMarks{
+			ReportNumber: chunkNumber,
+			IsLastReport: isLast,
+		}
+
+		response, err := api.postReport(ctx, settings, report, chunk, opts...)
+		if err != nil {
+			return "", fmt.Errorf("submitting report chunk #%d: %w", chunkNumber, err)
+		}
+
+		chunkNumber++
+		size = 0
+		chunk.resources = nil
+		chunk.results = nil
+
+		return response, nil
+	}
+
+	// add encodes an item once, measures it and appends it to the current chunk, after sending the chunk if it is full
+	add := func(item any, items *[]json.RawMessage) error {
+		jazon, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		if size+len(jazon) > maxChunkSize && (len(chunk.resources) > 0 || len(chunk.results) > 0) {
+			if _, err := send(false); err != nil {
+				return err
+			}
+		}
+
+		size += len(jazon)
+		*items = append(*items, jazon)
+
+		return nil
+	}
+
+	for i := range report.Resources {
+		if err := add(&report.Resources[i], &chunk.resources); err != nil {
+			return "", err
+		}
+	}
+
+	for i := range report.Results {
+		if err := add(&report.Results[i], &chunk.results); err != nil {
+			return "", err
+		}
+	}
+
+	return send(true)
+}
+
+// postReport posts a posture report (or a chunk thereof) and returns the response body.
+//
+// doc is either the report or a *reportChunk of it, and is streamed to the server as it is being encoded.
+func (api *KSCloudAPI) postReport(ctx context.Context, settings ksCloudSettings, report *PostureReport, doc any, opts ...RequestOption) (string, error) {
+	rdr, _, err := api.postStream(ctx, api.postReportURL(settings, report.ClusterName, report.ReportID), doc,
+		append([]RequestOption{WithContentJSON(true), WithIdempotent(true), withSettings(settings)}, opts...)...,
+	)
+	if err != nil {
+		return "", err
+	}
+	defer rdr.Close()
+
+	b, err := io.ReadAll(rdr)
+	if err == nil {
+		return string(b), nil
+	}
+	return "", err
+}
@@ -0,0 +1,252 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	backendServer "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkRecorder records the posture report chunks posted to the report endpoint.
+type chunkRecorder struct {
+	mu        sync.Mutex
+	chunks    []PostureReport
+	reportIDs []string
+	encodings []string
+}
+
+func (c *chunkRecorder) server(t testing.TB) *httptest.Server {
+	h := http.NewServeMux()
+	h.HandleFunc(backendServer.ReporterReportPath, func(w http.ResponseWriter, r *http.Request) {
+		buf, err := readBody(r)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		var chunk PostureReport
+		if !assert.NoError(t, json.Unmarshal(buf, &chunk)) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		c.mu.Lock()
+		c.chunks = append(c.chunks, chunk)
+		c.reportIDs = append(c.reportIDs, r.URL.Query().Get(backendServer.QueryParamReport))
+		c.encodings = append(c.encodings, r.Header.Get("Content-Encoding"))
+		c.mu.Unlock()
+
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestSubmitReportCompressed(t *testing.T) {
+	t.Parallel()
+
+	srv := MockAPIServer(t)
+	t.Cleanup(srv.Close)
+
+	for _, compression := range []Compression{CompressionGzip, CompressionZstd} {
+		t.Run("should submit report with "+string(compression), func(t *testing.T) {
+			t.Parallel()
+
+			ks, err := NewKSCloudAPI(srv.Root(), srv.Root(), "account", "", WithReportCompression(compression))
+			require.NoError(t, err)
+
+			_, err = ks.SubmitReport(mockPostureReport(t, "", ""))
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("should override compression per request", func(t *testing.T) {
+		t.Parallel()
+
+		var recorder chunkRecorder
+		rec := recorder.server(t)
+
+		ks, err := NewKSCloudAPI(rec.URL, rec.URL, "account", "", WithReportCompression(CompressionGzip))
+		require.NoError(t, err)
+
+		_, err = ks.SubmitReportCtx(t.Context(), mockPostureReport(t, "", ""), WithRequestCompression(CompressionZstd))
+		require.NoError(t, err)
+		require.Equal(t, []string{"zstd"}, recorder.encodings)
+	})
+}
+
+func TestSubmitReportInChunks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should split resources and results into sequenced chunks", func(t *testing.T) {
+		t.Parallel()
+
+		var recorder chunkRecorder
+		srv := recorder.server(t)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithReportChunking(1), WithReportCompression(CompressionGzip))
+		require.NoError(t, err)
+
+		report := mockPostureReport(t, "", "")
+		response, err := ks.SubmitReport(report)
+		require.NoError(t, err)
+		require.Equal(t, "ok", response)
+
+		// with a tiny chunk size, every resource and result is sent in a chunk of its own
+		expectedChunks := len(report.Resources) + len(report.Results)
+		require.Len(t, recorder.chunks, expectedChunks)
+
+		var resources, results int
+		for i, chunk := range recorder.chunks {
+			require.Equal(t, i, chunk.PaginationInfo.ReportNumber)
+			require.Equal(t, i == expectedChunks-1, chunk.PaginationInfo.IsLastReport)
+			require.Equal(t, report.ReportID, chunk.ReportID)
+			require.Equal(t, report.ReportID, recorder.reportIDs[i])
+			require.Equal(t, "gzip", recorder.encodings[i])
+			resources += len(chunk.Resources)
+			results += len(chunk.Results)
+		}
+
+		require.Equal(t, len(report.Resources), resources)
+		require.Equal(t, len(report.Results), results)
+	})
+
+	t.Run("should send a single chunk when the report fits", func(t *testing.T) {
+		t.Parallel()
+
+		var recorder chunkRecorder
+		srv := recorder.server(t)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "")
+		require.NoError(t, err)
+
+		report := mockPostureReport(t, "", "")
+		_, err = ks.SubmitReportCtx(t.Context(), report, WithRequestChunking(100<<20))
+		require.NoError(t, err)
+
+		require.Len(t, recorder.chunks, 1)
+		require.True(t, recorder.chunks[0].PaginationInfo.IsLastReport)
+		require.Len(t, recorder.chunks[0].Resources, len(report.Resources))
+		require.Len(t, recorder.chunks[0].Results, len(report.Results))
+
+		// the items are encoded once, when measured, then sent as is
+		expected, err := json.Marshal(report.Results)
+		require.NoError(t, err)
+		actual, err := json.Marshal(recorder.chunks[0].Results)
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), string(actual))
+	})
+
+	t.Run("should report chunk errors", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}))
+		t.Cleanup(srv.Close)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithReportChunking(1))
+		require.NoError(t, err)
+
+		_, err = ks.SubmitReport(mockPostureReport(t, "", ""))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "chunk #0")
+	})
+}
+
+func TestSubmitReportStreamed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should re-encode the streamed report when retrying", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 1, http.StatusServiceUnavailable, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(fastRetryPolicy()))
+		require.NoError(t, err)
+
+		report := mockPostureReport(t, "", "")
+		response, err := ks.SubmitReport(report)
+		require.NoError(t, err)
+		require.Equal(t, int32(2), calls.Load())
+
+		var echoed PostureReport
+		require.NoError(t, json.Unmarshal([]byte(response), &echoed))
+		require.Equal(t, report.ReportID, echoed.ReportID)
+		require.Len(t, echoed.Resources, len(report.Resources))
+	})
+
+	t.Run("should stream a compressed report", func(t *testing.T) {
+		t.Parallel()
+
+		var recorder chunkRecorder
+		srv := recorder.server(t)
+
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithReportCompression(CompressionZstd))
+		require.NoError(t, err)
+
+		report := mockPostureReport(t, "", "")
+		_, err = ks.SubmitReport(report)
+		require.NoError(t, err)
+		require.Len(t, recorder.chunks, 1)
+		require.Equal(t, report.ReportID, recorder.chunks[0].ReportID)
+	})
+	t.Run("should encode a report as json.Marshal does", func(t *testing.T) {
+		t.Parallel()
+
+		report := mockPostureReport(t, "", "")
+		expected, err := json.Marshal(report)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, writeJSON(&buf, report))
+		require.JSONEq(t, string(expected), buf.String())
+
+		report.Resources = nil
+		report.Results = nil
+		expected, err = json.Marshal(report)
+		require.NoError(t, err)
+
+		buf.Reset()
+		require.NoError(t, writeJSON(&buf, report))
+		require.JSONEq(t, string(expected), buf.String())
+	})
+
+	t.Run("should not hold the whole encoded report in memory", func(t *testing.T) {
+		t.Parallel()
+
+		report := mockPostureReport(t, "", "")
+		resources, results := report.Resources, report.Results
+		for i := 1; i < 50; i++ {
+			report.Resources = append(report.Resources, resources...)
+			report.Results = append(report.Results, results...)
+		}
+
+		var w largestWriteRecorder
+		require.NoError(t, writeJSON(&w, report))
+		require.Less(t, w.largest, w.total/10)
+	})
+}
+
+// largestWriteRecorder records the total size of the data written, and the size of the largest write.
+type largestWriteRecorder struct {
+	total   int
+	largest int
+}
+
+func (w *largestWriteRecorder) Write(p []byte) (int, error) {
+	w.total += len(p)
+	w.largest = max(w.largest, len(p))
+
+	return len(p), nil
+}
@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	_ ResponseCache = &MemoryResponseCache{}
+	_ ResponseCache = &DiskResponseCache{}
+)
+
+type (
+	// ResponseCache stores API responses along with their validators (ETag, Last-Modified),
+	// so that subsequent requests may be sent as conditional requests.
+	ResponseCache interface {
+		// Get retrieves a cached response by key. It returns false if no entry was found.
+		Get(key string) (*CachedResponse, bool)
+
+		// Set stores a response under some key.
+		Set(key string, entry *CachedResponse) error
+	}
+
+	// CachedResponse is a cached response body with its validators.
+	CachedResponse struct {
+		ETag         string `json:"etag,omitempty"`
+		LastModified string `json:"lastModified,omitempty"`
+		Body         []byte `json:"body"`
+	}
+
+	// MemoryResponseCache is an in-memory ResponseCache, safe for concurrent use.
+	MemoryResponseCache struct {
+		mu      sync.RWMutex
+		entries map[string]*CachedResponse
+	}
+
+	// DiskResponseCache is a ResponseCache that persists entries as files in a directory.
+	DiskResponseCache struct {
+		dir string
+		mu  sync.RWMutex
+	}
+)
+
+// NewMemoryResponseCache builds an empty in-memory response cache.
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{
+		entries: make(map[string]*CachedResponse),
+	}
+}
+
+func (c *MemoryResponseCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+
+	return entry, ok
+}
+
+func (c *MemoryResponseCache) Set(key string, entry *CachedResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+
+	return nil
+}
+
+// NewDiskResponseCache builds a response cache persisted in the given directory.
+//
+// The directory is created if it doesn't exist.
+func NewDiskResponseCache(dir string) (*DiskResponseCache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &DiskResponseCache{
+		dir: dir,
+	}, nil
+}
+
+func (c *DiskResponseCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	buf, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry CachedResponse
+	if err := json.Unmarshal(buf, &entry); err != nil {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+func (c *DiskResponseCache) Set(key string, entry *CachedResponse) error {
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// write to a temporary file first, so a concurrent reader never sees a partial entry
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+func (c *DiskResponseCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// getCached sends a conditional GET request when a cached response is available,
+// and serves the cached body whenever the server responds 304 Not Modified.
+//
+// Responses carrying an ETag or Last-Modified header are stored in the cache.
+func (api *KSCloudAPI) getCached(ctx context.Context, fullURL string, opts ...RequestOption) (io.ReadCloser, int64, error) {
+	if api.responseCache == nil {
+		return api.get(ctx, fullURL, opts...)
+	}
+
+	o := api.defaultRequestOptions(ctx, opts)
+	req, err := http.NewRequestWithContext(o.reqContext, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cached, isCached := api.responseCache.Get(fullURL)
+	if isCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := api.doResponse(req, o)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		if !isCached {
+			return nil, 0, errors.New("unexpected 304 Not Modified response for an uncached request")
+		}
+
+		return io.NopCloser(bytes.NewReader(cached.Body)), int64(len(cached.Body)), nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entry := &CachedResponse{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Body:         body,
+	}
+
+	if entry.ETag != "" || entry.LastModified != "" {
+		// a cache failure is not fatal: the response is still served
+		_ = api.responseCache.Set(fullURL, entry)
+	}
+
+	return io.NopCloser(bytes.NewReader(body)), int64(len(body)), nil
+}
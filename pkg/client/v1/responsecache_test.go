@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	backendServer "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// etagServer serves frameworks with an ETag, and responds 304 when the client sends a matching If-None-Match.
+func etagServer(t testing.TB) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	const etag = `"v1"`
+	var full, notModified atomic.Int32
+
+	h := http.NewServeMux()
+	h.HandleFunc(backendServer.ApiServerFrameworksPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		full.Add(1)
+		w.Header().Set("ETag", etag)
+		_ = json.NewEncoder(w).Encode(mockFrameworks())
+	})
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	return srv, &full, &notModified
+}
+
+func TestResponseCache(t *testing.T) {
+	t.Parallel()
+
+	diskCache, err := NewDiskResponseCache(t.TempDir())
+	require.NoError(t, err)
+
+	for name, cache := range map[string]ResponseCache{
+		"memory": NewMemoryResponseCache(),
+		"disk":   diskCache,
+	} {
+		t.Run("should serve frameworks from the "+name+" cache on 304", func(t *testing.T) {
+			t.Parallel()
+
+			srv, full, notModified := etagServer(t)
+			ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithResponseCache(cache))
+			require.NoError(t, err)
+
+			expected := mockFrameworks()
+			for range 3 {
+				frameworks, err := ks.GetFrameworks()
+				require.NoError(t, err)
+				require.EqualValues(t, expected, frameworks)
+			}
+
+			require.Equal(t, int32(1), full.Load())
+			require.Equal(t, int32(2), notModified.Load())
+		})
+	}
+
+	t.Run("should not send conditional requests without a cache", func(t *testing.T) {
+		t.Parallel()
+
+		srv, full, notModified := etagServer(t)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "")
+		require.NoError(t, err)
+
+		for range 2 {
+			_, err := ks.GetFrameworks()
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, int32(2), full.Load())
+		require.Zero(t, notModified.Load())
+	})
+
+	t.Run("disk cache should persist entries", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		cache, err := NewDiskResponseCache(dir)
+		require.NoError(t, err)
+
+		entry := &CachedResponse{ETag: `"x"`, LastModified: "yesterday", Body: []byte(`{}`)}
+		require.NoError(t, cache.Set("key", entry))
+
+		reopened, err := NewDiskResponseCache(dir)
+		require.NoError(t, err)
+
+		cached, ok := reopened.Get("key")
+		require.True(t, ok)
+		require.Equal(t, entry, cached)
+
+		_, ok = reopened.Get("other")
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,301 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy determines how failed requests to the KS Cloud API are retried.
+//
+// Only GET requests and requests flagged as idempotent (e.g. posture report submissions,
+// which are keyed by their report GUID) are retried.
+//
+// Fields left to zero take the value of DefaultRetryPolicy. A negative MaxInterval, Jitter or MaxElapsedTime
+// disables the cap, the jitter or the budget.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. A negative value means no limit.
+	MaxAttempts int
+
+	// InitialInterval is the delay before the first retry.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the exponential backoff delay (not the delay advertised by a Retry-After header).
+	// A negative value means no cap.
+	MaxInterval time.Duration
+
+	// Multiplier is the growth factor of the delay between two consecutive retries. Values below 1 take the default.
+	Multiplier float64
+
+	// Jitter randomizes the delay by +/- this fraction, in [0, 1]. A negative value means no jitter.
+	Jitter float64
+
+	// MaxElapsedTime stops retrying once the total time spent would exceed this budget. A negative value means no limit.
+	MaxElapsedTime time.Duration
+
+	// Retryable decides if a response or a network error should be retried.
+	//
+	// Defaults to DefaultRetryable.
+	Retryable func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy returns a retry policy with sensible defaults:
+// 4 attempts, exponential backoff starting at 500ms, with a 50% jitter and a 2m budget.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:     4,
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     30 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.5,
+		MaxElapsedTime:  2 * time.Minute,
+		Retryable:       DefaultRetryable,
+	}
+}
+
+// DefaultRetryable retries network errors (except context cancellation or deadline, or an open circuit breaker),
+// as well as 408, 429, 500, 502, 503 and 504 status codes.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrCircuitOpen)
+	}
+
+	if resp == nil {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// withDefaults returns a copy of the policy, where unset fields take the value of DefaultRetryPolicy.
+//
+// This prevents a partially filled policy from retrying forever, without any delay.
+func (p *RetryPolicy) withDefaults() *RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	policy := *p
+
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if policy.InitialInterval <= 0 {
+		policy.InitialInterval = defaults.InitialInterval
+	}
+
+	if policy.Multiplier < 1 {
+		policy.Multiplier = defaults.Multiplier
+	}
+
+	if policy.MaxInterval == 0 {
+		policy.MaxInterval = defaults.MaxInterval
+	}
+
+	if policy.Jitter == 0 {
+		policy.Jitter = defaults.Jitter
+	}
+
+	if policy.MaxElapsedTime == 0 {
+		policy.MaxElapsedTime = defaults.MaxElapsedTime
+	}
+
+	return &policy
+}
+
+func (p *RetryPolicy) isRetryable(resp *http.Response, err error) bool {
+	if p.Retryable == nil {
+		return DefaultRetryable(resp, err)
+	}
+
+	return p.Retryable(resp, err)
+}
+
+// backoff computes the delay before the next attempt.
+//
+// A Retry-After header sent by the server takes precedence over the exponential backoff.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := retryAfter(resp); ok {
+			return delay
+		}
+	}
+
+	delay := float64(p.InitialInterval)
+	for i := 1; i < attempt; i++ {
+		delay *= p.Multiplier
+		if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+			delay = float64(p.MaxInterval)
+
+			break
+		}
+	}
+
+	if p.Jitter > 0 {
+		delta := p.Jitter * delay
+		delay = delay - delta + rand.Float64()*2*delta //nolint:gosec
+	}
+
+	if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+		delay = float64(p.MaxInterval)
+	}
+
+	return time.Duration(delay)
+}
+
+// retryAfter parses a Retry-After header, expressed either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// doWithRetry sends a request and retries it according to the retry policy, when applicable.
+func (api *KSCloudAPI) doWithRetry(req *http.Request, o *RequestOptions) (*http.Response, error) {
+	policy := api.retryPolicy
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if policy == nil || !replayable || (req.Method != http.MethodGet && !o.idempotent) {
+		return api.roundTrip(req, o)
+	}
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		resp, err := api.roundTrip(req, o)
+		if (policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts) || !policy.isRetryable(resp, err) {
+			return resp, err
+		}
+
+		delay := policy.backoff(attempt, resp)
+		if policy.MaxElapsedTime > 0 && time.Since(start)+delay > policy.MaxElapsedTime {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		if err := sleepWithContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// roundTrip sends a single request, with tracing, rate limiting and circuit breaking.
+func (api *KSCloudAPI) roundTrip(req *http.Request, o *RequestOptions) (*http.Response, error) {
+	if api.circuitBreakers == nil {
+		return api.throttledRoundTrip(req, o)
+	}
+
+	breaker := api.circuitBreakers.forHost(req.URL.Host)
+	generation, err := breaker.allow()
+	if err != nil {
+		closeRequestBody(req)
+
+		return nil, err
+	}
+
+	resp, err := api.throttledRoundTrip(req, o)
+	breaker.record(generation, resp, err)
+
+	return resp, err
+}
+
+// throttledRoundTrip sends a single request, with tracing and rate limiting.
+func (api *KSCloudAPI) throttledRoundTrip(req *http.Request, o *RequestOptions) (*http.Response, error) {
+	limiter := api.rateLimiterFor(req)
+	if limiter != nil {
+		release, err := limiter.acquire(req.Context())
+		if err != nil {
+			closeRequestBody(req)
+
+			return nil, err
+		}
+
+		resp, err := api.send(req, o)
+		if err != nil {
+			release()
+
+			return nil, err
+		}
+
+		limiter.observe(resp)
+		resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+		return resp, nil
+	}
+
+	return api.send(req, o)
+}
+
+// send sends a single request, with tracing and telemetry.
+func (api *KSCloudAPI) send(req *http.Request, o *RequestOptions) (*http.Response, error) {
+	return api.telemetry.roundTrip(req, func(req *http.Request) (*http.Response, error) {
+		if err := api.authenticate(req, *o.settings); err != nil {
+			closeRequestBody(req)
+
+			return nil, err
+		}
+		o.traceReq(req)
+
+		resp, err := api.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		o.traceResp(resp)
+
+		return resp, nil
+	})
+}
+
+// closeRequestBody closes the body of a request which is not sent, as http.Client.Do would.
+//
+// This terminates the goroutine encoding a streamed body, which would otherwise block forever.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
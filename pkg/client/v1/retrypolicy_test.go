@@ -0,0 +1,251 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	backendServer "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func fastRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     10 * time.Millisecond,
+		Multiplier:      2,
+	}
+}
+
+// flakyServer fails the first n calls with the given status code, then echoes the request body.
+func flakyServer(t testing.TB, n int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			for k, vals := range header {
+				for _, v := range vals {
+					w.Header().Add(k, v)
+				}
+			}
+			w.WriteHeader(status)
+
+			return
+		}
+
+		echoBody(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should retry GET on 502", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 2, http.StatusBadGateway, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(fastRetryPolicy()))
+		require.NoError(t, err)
+
+		rdr, _, err := ks.get(context.Background(), srv.URL+pathTestGet)
+		require.NoError(t, err)
+		_ = rdr.Close()
+		require.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("should apply the defaults to a zero-value policy", func(t *testing.T) {
+		t.Parallel()
+
+		ks, err := NewKSCloudAPI("http://localhost", "http://localhost", "account", "", WithRetryPolicy(&RetryPolicy{}))
+		require.NoError(t, err)
+
+		defaults := DefaultRetryPolicy()
+		require.Equal(t, defaults.MaxAttempts, ks.retryPolicy.MaxAttempts)
+		require.Equal(t, defaults.InitialInterval, ks.retryPolicy.InitialInterval)
+		require.Equal(t, defaults.Multiplier, ks.retryPolicy.Multiplier)
+		require.Equal(t, defaults.MaxInterval, ks.retryPolicy.MaxInterval)
+		require.Equal(t, defaults.Jitter, ks.retryPolicy.Jitter)
+		require.Equal(t, defaults.MaxElapsedTime, ks.retryPolicy.MaxElapsedTime)
+	})
+
+	t.Run("should disable the cap, jitter and budget of a policy with negative values", func(t *testing.T) {
+		t.Parallel()
+
+		ks, err := NewKSCloudAPI("http://localhost", "http://localhost", "account", "", WithRetryPolicy(&RetryPolicy{
+			InitialInterval: time.Minute,
+			MaxInterval:     -1,
+			Jitter:          -1,
+			MaxElapsedTime:  -1,
+		}))
+		require.NoError(t, err)
+
+		require.Equal(t, 8*time.Minute, ks.retryPolicy.backoff(4, nil))
+	})
+
+	t.Run("should give up after the default max attempts with a partial policy", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 10, http.StatusServiceUnavailable, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(&RetryPolicy{InitialInterval: time.Millisecond}))
+		require.NoError(t, err)
+
+		_, _, err = ks.get(context.Background(), srv.URL+pathTestGet)
+		require.Error(t, err)
+		require.Equal(t, int32(DefaultRetryPolicy().MaxAttempts), calls.Load())
+	})
+
+	t.Run("should give up after max attempts", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 10, http.StatusServiceUnavailable, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(fastRetryPolicy()))
+		require.NoError(t, err)
+
+		_, _, err = ks.get(context.Background(), srv.URL+pathTestGet)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "503")
+		require.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("should not retry without a policy", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 1, http.StatusBadGateway, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "")
+		require.NoError(t, err)
+
+		_, _, err = ks.get(context.Background(), srv.URL+pathTestGet)
+		require.Error(t, err)
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should not retry non-retryable status", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 1, http.StatusNotFound, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(fastRetryPolicy()))
+		require.NoError(t, err)
+
+		_, _, err = ks.get(context.Background(), srv.URL+pathTestGet)
+		require.Error(t, err)
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should not retry non-idempotent POST", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 1, http.StatusBadGateway, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(fastRetryPolicy()))
+		require.NoError(t, err)
+
+		_, _, err = ks.post(context.Background(), srv.URL+pathTestPost, []byte(`{}`))
+		require.Error(t, err)
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should retry idempotent POST and replay the body", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 1, http.StatusBadGateway, nil)
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(fastRetryPolicy()))
+		require.NoError(t, err)
+
+		const payload = `{"key":"value"}`
+		rdr, _, err := ks.post(context.Background(), srv.URL+backendServer.ReporterReportPath, []byte(payload), WithIdempotent(true))
+		require.NoError(t, err)
+		defer rdr.Close()
+
+		body, err := io.ReadAll(rdr)
+		require.NoError(t, err)
+		require.Equal(t, payload, string(body))
+		require.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should honor Retry-After", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}})
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(fastRetryPolicy()))
+		require.NoError(t, err)
+
+		start := time.Now()
+		rdr, _, err := ks.get(context.Background(), srv.URL+pathTestGet)
+		require.NoError(t, err)
+		_ = rdr.Close()
+		require.GreaterOrEqual(t, time.Since(start), time.Second)
+		require.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should stop when the max elapsed time is exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 10, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"60"}})
+		policy := fastRetryPolicy()
+		policy.MaxElapsedTime = time.Second
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(policy))
+		require.NoError(t, err)
+
+		_, _, err = ks.get(context.Background(), srv.URL+pathTestGet)
+		require.Error(t, err)
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should stop when the context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		srv, _ := flakyServer(t, 10, http.StatusBadGateway, nil)
+		policy := fastRetryPolicy()
+		policy.InitialInterval = time.Minute
+		policy.MaxInterval = time.Minute
+		ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "", WithRetryPolicy(policy))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, _, err = ks.get(ctx, srv.URL+pathTestGet)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     time.Second,
+		Multiplier:      2,
+	}
+
+	require.Equal(t, 100*time.Millisecond, policy.backoff(1, nil))
+	require.Equal(t, 200*time.Millisecond, policy.backoff(2, nil))
+	require.Equal(t, 400*time.Millisecond, policy.backoff(3, nil))
+	require.Equal(t, time.Second, policy.backoff(10, nil))
+
+	policy.Jitter = 0.5
+	for range 100 {
+		delay := policy.backoff(2, nil)
+		require.GreaterOrEqual(t, delay, 100*time.Millisecond)
+		require.LessOrEqual(t, delay, 300*time.Millisecond)
+	}
+}
+
+func TestDefaultRetryable(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, DefaultRetryable(nil, errors.New("connection reset")))
+	require.False(t, DefaultRetryable(nil, context.Canceled))
+	require.True(t, DefaultRetryable(&http.Response{StatusCode: http.StatusTooManyRequests}, nil))
+	require.True(t, DefaultRetryable(&http.Response{StatusCode: http.StatusBadGateway}, nil))
+	require.False(t, DefaultRetryable(&http.Response{StatusCode: http.StatusUnauthorized}, nil))
+	require.False(t, DefaultRetryable(&http.Response{StatusCode: http.StatusOK}, nil))
+}
@@ -2,12 +2,14 @@ package v1
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/armosec/armoapi-go/armotypes"
 	"github.com/kubescape/backend/pkg/client/v1/proto"
 	backendv1 "github.com/kubescape/backend/pkg/server/v1"
 	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
@@ -32,16 +34,29 @@ type GRPCConfig struct {
 }
 
 // StorageClient provides a gRPC client for the Kubescape storage server
+//
+// The credentials and cluster name may be updated while calls are in flight.
 type StorageClient struct {
 	*StorageClientOptions
-	accountID   string
-	accessKey   string
-	cluster     string
+	mu          sync.Mutex // serializes updates of the identity
+	identity    atomic.Pointer[storageIdentity]
 	address     string // host:port format
 	grpcConfig  *GRPCConfig
 	conn        *grpc.ClientConn
 	protoClient proto.StorageServiceClient
-	metadata    metadata.MD
+
+	// streamingUnsupported is set once the server is found not to support streaming RPCs
+	streamingUnsupported atomic.Bool
+}
+
+// storageIdentity holds the credentials sent with every call, along with the gRPC metadata built from them.
+//
+// An identity is never mutated: updates swap in a new one, so that every call sends a consistent set of metadata.
+type storageIdentity struct {
+	accountID string
+	accessKey string
+	cluster   string
+	metadata  metadata.MD
 }
 
 // ParseGRPCURL parses a gRPC URL and returns the configuration
@@ -125,60 +140,80 @@ func NewStorageClient(grpcURL, accountID, accessKey, cluster string, opts ...Sto
 
 	client := &StorageClient{
 		StorageClientOptions: storageClientOptionsWithDefaults(opts),
-		accountID:            accountID,
-		accessKey:            accessKey,
-		cluster:              cluster,
 		address:              fmt.Sprintf("%s:%d", config.Host, config.Port),
 		grpcConfig:           config,
 	}
 
-	client.refreshMetadata()
+	client.updateIdentity(func(identity *storageIdentity) {
+		identity.accountID = accountID
+		identity.accessKey = accessKey
+		identity.cluster = cluster
+	})
 
 	return client, nil
 }
 
 // SetAccountID sets the customer account GUID
 func (c *StorageClient) SetAccountID(value string) {
-	c.accountID = value
-	c.refreshMetadata()
+	c.updateIdentity(func(identity *storageIdentity) {
+		identity.accountID = value
+	})
 }
 
 // SetAccessKey sets the API access key
 func (c *StorageClient) SetAccessKey(value string) {
-	c.accessKey = value
-	c.refreshMetadata()
+	c.updateIdentity(func(identity *storageIdentity) {
+		identity.accessKey = value
+	})
 }
 
 // SetCluster sets the cluster name
 func (c *StorageClient) SetCluster(value string) {
-	c.cluster = value
-	c.refreshMetadata()
+	c.updateIdentity(func(identity *storageIdentity) {
+		identity.cluster = value
+	})
+}
+
+// currentIdentity returns the identity sent with calls
+func (c *StorageClient) currentIdentity() *storageIdentity {
+	if identity := c.identity.Load(); identity != nil {
+		return identity
+	}
+
+	return &storageIdentity{}
 }
 
-// refreshMetadata rebuilds the gRPC metadata with current credentials
-func (c *StorageClient) refreshMetadata() {
-	c.metadata = metadata.Pairs(
-		backendv1.GrpcAccessKeyHeader, c.accessKey,
-		backendv1.GrpcAccountKey, c.accountID,
-		backendv1.GrpcClusterKey, c.cluster,
+// updateIdentity applies a change to a copy of the current identity, rebuilds the gRPC metadata, then swaps it in
+func (c *StorageClient) updateIdentity(update func(*storageIdentity)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	identity := *c.currentIdentity()
+	update(&identity)
+	identity.metadata = metadata.Pairs(
+		backendv1.GrpcAccessKeyHeader, identity.accessKey,
+		backendv1.GrpcAccountKey, identity.accountID,
+		backendv1.GrpcClusterKey, identity.cluster,
 		backendv1.GrpcHostTypeKey, c.hostType,
 		backendv1.GrpcHostIDKey, c.hostID,
 	)
+
+	c.identity.Store(&identity)
 }
 
 // GetAccountID returns the customer account GUID
 func (c *StorageClient) GetAccountID() string {
-	return c.accountID
+	return c.currentIdentity().accountID
 }
 
 // GetAccessKey returns the API access key
 func (c *StorageClient) GetAccessKey() string {
-	return c.accessKey
+	return c.currentIdentity().accessKey
 }
 
 // GetCluster returns the cluster name
 func (c *StorageClient) GetCluster() string {
-	return c.cluster
+	return c.currentIdentity().cluster
 }
 
 // GetAddress returns the storage server address
@@ -202,19 +237,37 @@ func (c *StorageClient) Connect() error {
 
 	// Determine if connection should be secure
 	if c.grpcConfig != nil && c.grpcConfig.IsSecure {
-		// Use TLS with system CA certificates (for ingress-terminated TLS)
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+		// Use TLS, with system CA certificates unless configured otherwise (e.g. an internal PKI)
+		tlsConfig, err := c.tls.config(c.grpcConfig.Host)
+		if err != nil {
+			return fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		// Use insecure credentials
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
+	// Keepalive, reconnection backoff, retries and health checking
+	connOpts, err := c.dialOptions()
+	if err != nil {
+		return err
+	}
+	dialOpts = append(dialOpts, connOpts...)
+
+	// Trace calls and propagate the trace context to the server
+	dialOpts = append(dialOpts,
+		grpc.WithChainUnaryInterceptor(c.telemetry.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(c.telemetry.streamInterceptor),
+	)
+
 	conn, err := grpc.NewClient(c.address, dialOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to connect to storage server: %w", err)
 	}
 	c.conn = conn
 	c.protoClient = proto.NewStorageServiceClient(conn)
+	c.streamingUnsupported.Store(false)
 
 	return nil
 }
@@ -231,14 +284,17 @@ func (c *StorageClient) Close() error {
 	return err
 }
 
-// IsConnected returns true if the client is connected to the server
+// IsConnected returns true if Connect was called and the client was not closed since
+// The live state of the connection is reported by State.
 func (c *StorageClient) IsConnected() bool {
 	return c.conn != nil
 }
 
 // withMetadata returns a context with auth metadata attached
+//
+// The metadata of an identity is shared by calls and must not be modified.
 func (c *StorageClient) withMetadata(ctx context.Context) context.Context {
-	return metadata.NewOutgoingContext(ctx, c.metadata)
+	return metadata.NewOutgoingContext(ctx, c.currentIdentity().metadata)
 }
 
 // SendContainerProfile sends a container profile to the storage server
@@ -376,16 +432,94 @@ func (c *StorageClient) GetContainerProfile(ctx context.Context, namespace, name
 	return resp.ContainerProfile, nil
 }
 
+// DeleteProfile deletes a profile from the storage server, so that the behavior of the workload is learned again
+// e.g. after the workload is redeployed with new code
+func (c *StorageClient) DeleteProfile(ctx context.Context, kind armotypes.ProfileKind, namespace, name string, opts ...ProfileOption) error {
+	if c.protoClient == nil {
+		return fmt.Errorf("client is not connected")
+	}
+
+	profileOpts := profileOptionsWithDefaults(opts)
+
+	req := &proto.DeleteProfileRequest{
+		Kind:                   string(kind),
+		Namespace:              namespace,
+		Name:                   name,
+		Region:                 profileOpts.Region,
+		CloudAccountIdentifier: profileOpts.CloudAccountIdentifier,
+	}
+
+	ctx = c.withMetadata(ctx)
+
+	if c.callTimeout != nil && *c.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *c.callTimeout)
+		defer cancel()
+	}
+
+	resp, err := c.protoClient.DeleteProfile(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to delete %s: %s (code: %v)", kind, resp.ErrorMessage, resp.ErrorCode)
+	}
+
+	return nil
+}
+
+// CompleteProfile marks a profile as completed on the storage server, ending its learning period
+func (c *StorageClient) CompleteProfile(ctx context.Context, kind armotypes.ProfileKind, namespace, name string, opts ...ProfileOption) error {
+	if c.protoClient == nil {
+		return fmt.Errorf("client is not connected")
+	}
+
+	profileOpts := profileOptionsWithDefaults(opts)
+
+	req := &proto.CompleteProfileRequest{
+		Kind:                   string(kind),
+		Namespace:              namespace,
+		Name:                   name,
+		Region:                 profileOpts.Region,
+		CloudAccountIdentifier: profileOpts.CloudAccountIdentifier,
+	}
+
+	ctx = c.withMetadata(ctx)
+
+	if c.callTimeout != nil && *c.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *c.callTimeout)
+		defer cancel()
+	}
+
+	resp, err := c.protoClient.CompleteProfile(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to complete %s: %s (code: %v)", kind, resp.ErrorMessage, resp.ErrorCode)
+	}
+
+	return nil
+}
+
 // ListApplicationProfiles lists all ApplicationProfiles in a namespace (returns metadata only, nil Spec)
 // For backward compatibility, region and cloudAccountIdentifier can be provided via ProfileOption
 // Old way: ListApplicationProfiles(ctx, "ns", 100, "")
 // New way: ListApplicationProfiles(ctx, "ns", 100, "", WithProfileRegion("us-east-1"), WithProfileCloudAccountIdentifier("123"))
+// The list can span all namespaces, and be filtered by label and field selectors, with ProfileOption:
+// ListApplicationProfiles(ctx, "", 100, "", WithProfileAllNamespaces(), WithProfileFieldSelector("workloadKind=Deployment"))
 func (c *StorageClient) ListApplicationProfiles(ctx context.Context, namespace string, limit int64, cont string, opts ...ProfileOption) (*v1beta1.ApplicationProfileList, error) {
 	if c.protoClient == nil {
 		return nil, fmt.Errorf("client is not connected")
 	}
 
 	profileOpts := profileOptionsWithDefaults(opts)
+	if err := profileOpts.validateList(namespace); err != nil {
+		return nil, err
+	}
 
 	req := &proto.ListApplicationProfilesRequest{
 		Namespace:              namespace,
@@ -393,6 +527,9 @@ func (c *StorageClient) ListApplicationProfiles(ctx context.Context, namespace s
 		Cont:                   cont,
 		Region:                 profileOpts.Region,
 		CloudAccountIdentifier: profileOpts.CloudAccountIdentifier,
+		LabelSelector:          profileOpts.LabelSelector,
+		FieldSelector:          profileOpts.FieldSelector,
+		AllNamespaces:          profileOpts.AllNamespaces,
 	}
 
 	ctx = c.withMetadata(ctx)
@@ -436,12 +573,17 @@ func (c *StorageClient) ListApplicationProfiles(ctx context.Context, namespace s
 // For backward compatibility, region and cloudAccountIdentifier can be provided via ProfileOption
 // Old way: ListNetworkNeighborhoods(ctx, "ns", 100, "")
 // New way: ListNetworkNeighborhoods(ctx, "ns", 100, "", WithProfileRegion("us-east-1"), WithProfileCloudAccountIdentifier("123"))
+// The list can span all namespaces, and be filtered by label and field selectors, with ProfileOption:
+// ListNetworkNeighborhoods(ctx, "", 100, "", WithProfileAllNamespaces(), WithProfileFieldSelector("workloadKind=Deployment"))
 func (c *StorageClient) ListNetworkNeighborhoods(ctx context.Context, namespace string, limit int64, cont string, opts ...ProfileOption) (*v1beta1.NetworkNeighborhoodList, error) {
 	if c.protoClient == nil {
 		return nil, fmt.Errorf("client is not connected")
 	}
 
 	profileOpts := profileOptionsWithDefaults(opts)
+	if err := profileOpts.validateList(namespace); err != nil {
+		return nil, err
+	}
 
 	req := &proto.ListNetworkNeighborhoodsRequest{
 		Namespace:              namespace,
@@ -449,6 +591,9 @@ func (c *StorageClient) ListNetworkNeighborhoods(ctx context.Context, namespace
 		Cont:                   cont,
 		Region:                 profileOpts.Region,
 		CloudAccountIdentifier: profileOpts.CloudAccountIdentifier,
+		LabelSelector:          profileOpts.LabelSelector,
+		FieldSelector:          profileOpts.FieldSelector,
+		AllNamespaces:          profileOpts.AllNamespaces,
 	}
 
 	ctx = c.withMetadata(ctx)
@@ -2,15 +2,22 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/armosec/armoapi-go/armotypes"
 	"github.com/kubescape/backend/pkg/client/v1/proto"
+	backendv1 "github.com/kubescape/backend/pkg/server/v1"
 	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // Mock StorageServiceClient for testing
@@ -19,6 +26,10 @@ type mockStorageServiceClient struct {
 	getProfileFunc               func(ctx context.Context, in *proto.GetProfileRequest, opts ...grpc.CallOption) (*proto.GetProfileResponse, error)
 	listApplicationProfilesFunc  func(ctx context.Context, in *proto.ListApplicationProfilesRequest, opts ...grpc.CallOption) (*proto.ListApplicationProfilesResponse, error)
 	listNetworkNeighborhoodsFunc func(ctx context.Context, in *proto.ListNetworkNeighborhoodsRequest, opts ...grpc.CallOption) (*proto.ListNetworkNeighborhoodsResponse, error)
+	streamContainerProfilesFunc  func(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[proto.StreamContainerProfilesRequest, proto.StreamContainerProfilesResponse], error)
+	watchProfilesFunc            func(ctx context.Context, in *proto.WatchProfilesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.WatchProfilesEvent], error)
+	deleteProfileFunc            func(ctx context.Context, in *proto.DeleteProfileRequest, opts ...grpc.CallOption) (*proto.DeleteProfileResponse, error)
+	completeProfileFunc          func(ctx context.Context, in *proto.CompleteProfileRequest, opts ...grpc.CallOption) (*proto.CompleteProfileResponse, error)
 }
 
 func (m *mockStorageServiceClient) SendContainerProfile(ctx context.Context, in *proto.SendContainerProfileRequest, opts ...grpc.CallOption) (*proto.SendContainerProfileResponse, error) {
@@ -49,6 +60,34 @@ func (m *mockStorageServiceClient) ListNetworkNeighborhoods(ctx context.Context,
 	return &proto.ListNetworkNeighborhoodsResponse{Success: true}, nil
 }
 
+func (m *mockStorageServiceClient) StreamContainerProfiles(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[proto.StreamContainerProfilesRequest, proto.StreamContainerProfilesResponse], error) {
+	if m.streamContainerProfilesFunc != nil {
+		return m.streamContainerProfilesFunc(ctx, opts...)
+	}
+	return nil, status.Error(codes.Unimplemented, "method StreamContainerProfiles not implemented")
+}
+
+func (m *mockStorageServiceClient) WatchProfiles(ctx context.Context, in *proto.WatchProfilesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.WatchProfilesEvent], error) {
+	if m.watchProfilesFunc != nil {
+		return m.watchProfilesFunc(ctx, in, opts...)
+	}
+	return nil, status.Error(codes.Unimplemented, "method WatchProfiles not implemented")
+}
+
+func (m *mockStorageServiceClient) DeleteProfile(ctx context.Context, in *proto.DeleteProfileRequest, opts ...grpc.CallOption) (*proto.DeleteProfileResponse, error) {
+	if m.deleteProfileFunc != nil {
+		return m.deleteProfileFunc(ctx, in, opts...)
+	}
+	return &proto.DeleteProfileResponse{Success: true}, nil
+}
+
+func (m *mockStorageServiceClient) CompleteProfile(ctx context.Context, in *proto.CompleteProfileRequest, opts ...grpc.CallOption) (*proto.CompleteProfileResponse, error) {
+	if m.completeProfileFunc != nil {
+		return m.completeProfileFunc(ctx, in, opts...)
+	}
+	return &proto.CompleteProfileResponse{Success: true}, nil
+}
+
 func TestNewStorageClient(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -318,6 +357,94 @@ func TestStorageClient_NotConnected(t *testing.T) {
 	assert.Contains(t, err.Error(), "not connected")
 }
 
+func TestStorageClient_DeleteProfile(t *testing.T) {
+	client, err := NewStorageClient("grpc://storage.example.com:50051", "test-account", "test-key", "test-cluster")
+	require.NoError(t, err)
+
+	t.Run("should delete the profile", func(t *testing.T) {
+		client.protoClient = &mockStorageServiceClient{
+			deleteProfileFunc: func(ctx context.Context, in *proto.DeleteProfileRequest, opts ...grpc.CallOption) (*proto.DeleteProfileResponse, error) {
+				assert.Equal(t, string(armotypes.ApplicationProfileKind), in.Kind)
+				assert.Equal(t, "default", in.Namespace)
+				assert.Equal(t, "my-app", in.Name)
+				assert.Equal(t, "us-east-1", in.Region)
+				assert.Equal(t, "123456789012", in.CloudAccountIdentifier)
+				return &proto.DeleteProfileResponse{Success: true}, nil
+			},
+		}
+
+		err := client.DeleteProfile(context.Background(), armotypes.ApplicationProfileKind, "default", "my-app", WithProfileRegion("us-east-1"), WithProfileCloudAccountIdentifier("123456789012"))
+		require.NoError(t, err)
+	})
+
+	t.Run("should report a failure", func(t *testing.T) {
+		client.protoClient = &mockStorageServiceClient{
+			deleteProfileFunc: func(ctx context.Context, in *proto.DeleteProfileRequest, opts ...grpc.CallOption) (*proto.DeleteProfileResponse, error) {
+				return &proto.DeleteProfileResponse{ErrorMessage: "not found", ErrorCode: proto.ErrorCode_ERROR_CODE_PROFILE_NOT_FOUND}, nil
+			},
+		}
+
+		err := client.DeleteProfile(context.Background(), armotypes.NetworkNeighborhoodKind, "default", "my-app")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ERROR_CODE_PROFILE_NOT_FOUND")
+	})
+
+	t.Run("should report a gRPC error", func(t *testing.T) {
+		client.protoClient = &mockStorageServiceClient{
+			deleteProfileFunc: func(ctx context.Context, in *proto.DeleteProfileRequest, opts ...grpc.CallOption) (*proto.DeleteProfileResponse, error) {
+				return nil, status.Error(codes.Unavailable, "unreachable")
+			},
+		}
+
+		err := client.DeleteProfile(context.Background(), armotypes.ContainerProfileKind, "default", "my-app")
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestStorageClient_CompleteProfile(t *testing.T) {
+	client, err := NewStorageClient("grpc://storage.example.com:50051", "test-account", "test-key", "test-cluster")
+	require.NoError(t, err)
+
+	t.Run("should complete the profile", func(t *testing.T) {
+		client.protoClient = &mockStorageServiceClient{
+			completeProfileFunc: func(ctx context.Context, in *proto.CompleteProfileRequest, opts ...grpc.CallOption) (*proto.CompleteProfileResponse, error) {
+				assert.Equal(t, string(armotypes.NetworkNeighborhoodKind), in.Kind)
+				assert.Equal(t, "kube-system", in.Namespace)
+				assert.Equal(t, "core-dns-nn", in.Name)
+				assert.Empty(t, in.Region)
+				assert.Empty(t, in.CloudAccountIdentifier)
+				return &proto.CompleteProfileResponse{Success: true}, nil
+			},
+		}
+
+		err := client.CompleteProfile(context.Background(), armotypes.NetworkNeighborhoodKind, "kube-system", "core-dns-nn")
+		require.NoError(t, err)
+	})
+
+	t.Run("should report a failure", func(t *testing.T) {
+		client.protoClient = &mockStorageServiceClient{
+			completeProfileFunc: func(ctx context.Context, in *proto.CompleteProfileRequest, opts ...grpc.CallOption) (*proto.CompleteProfileResponse, error) {
+				return &proto.CompleteProfileResponse{ErrorMessage: "already completed", ErrorCode: proto.ErrorCode_ERROR_CODE_PROFILE_COMPLETED}, nil
+			},
+		}
+
+		err := client.CompleteProfile(context.Background(), armotypes.ApplicationProfileKind, "default", "my-app")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already completed")
+	})
+}
+
+func TestStorageClient_DeleteAndCompleteProfile_NotConnected(t *testing.T) {
+	client, err := NewStorageClient("grpc://storage.example.com:50051", "test-account", "test-key", "test-cluster")
+	require.NoError(t, err)
+
+	err = client.DeleteProfile(context.Background(), armotypes.ApplicationProfileKind, "default", "my-app")
+	assert.ErrorContains(t, err, "not connected")
+
+	err = client.CompleteProfile(context.Background(), armotypes.ApplicationProfileKind, "default", "my-app")
+	assert.ErrorContains(t, err, "not connected")
+}
+
 func TestStorageClient_ListApplicationProfiles(t *testing.T) {
 	client, err := NewStorageClient("grpc://storage.example.com:50051", "test-account", "test-key", "test-cluster")
 	require.NoError(t, err)
@@ -615,3 +742,61 @@ func TestParseGRPCURL(t *testing.T) {
 		})
 	}
 }
+
+func TestStorageClient_ConcurrentRotation(t *testing.T) {
+	client, err := NewStorageClient("grpc://storage.example.com:50051", "account-0", "key-0", "cluster-0")
+	require.NoError(t, err)
+
+	client.protoClient = &mockStorageServiceClient{
+		sendContainerProfileFunc: func(ctx context.Context, _ *proto.SendContainerProfileRequest, _ ...grpc.CallOption) (*proto.SendContainerProfileResponse, error) {
+			md, ok := metadata.FromOutgoingContext(ctx)
+			assert.True(t, ok)
+
+			for key, prefix := range map[string]string{
+				backendv1.GrpcAccessKeyHeader: "key-",
+				backendv1.GrpcAccountKey:      "account-",
+				backendv1.GrpcClusterKey:      "cluster-",
+			} {
+				values := md.Get(key)
+				if assert.Len(t, values, 1, key) {
+					assert.True(t, strings.HasPrefix(values[0], prefix), "unexpected %s: %s", key, values[0])
+				}
+			}
+
+			return &proto.SendContainerProfileResponse{Success: true}, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; ctx.Err() == nil; i++ {
+			client.SetAccountID(fmt.Sprintf("account-%d", i))
+			client.SetAccessKey(fmt.Sprintf("key-%d", i))
+			client.SetCluster(fmt.Sprintf("cluster-%d", i))
+		}
+	}()
+
+	var callers sync.WaitGroup
+	for range 8 {
+		callers.Add(1)
+		go func() {
+			defer callers.Done()
+
+			for range 100 {
+				resp, err := client.SendContainerProfile(context.Background(), &v1beta1.ContainerProfile{})
+				assert.NoError(t, err)
+				assert.True(t, resp.GetSuccess())
+				assert.True(t, strings.HasPrefix(client.GetAccessKey(), "key-"))
+			}
+		}()
+	}
+
+	callers.Wait()
+	cancel()
+	wg.Wait()
+}
@@ -2,6 +2,11 @@ package v1
 
 import (
 	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/keepalive"
 )
 
 // StorageClientOption allows to configure the behavior of the Storage client
@@ -13,6 +18,18 @@ type StorageClientOptions struct {
 	withTrace   bool
 	hostType    string
 	hostID      string
+	tls         storageTLSOptions
+
+	keepalive      *keepalive.ClientParameters
+	retryPolicy    *StorageRetryPolicy
+	connectBackoff *backoff.Config
+	healthCheck    bool
+	healthService  string
+	batchSize      int
+
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	telemetry      *telemetry
 }
 
 // WithCallTimeout sets the timeout for individual gRPC calls
@@ -48,6 +65,23 @@ func WithHostID(hostID string) StorageClientOption {
 	}
 }
 
+// WithStorageTracerProvider sets the OpenTelemetry tracer provider used to trace gRPC calls
+// The W3C trace context is propagated to the storage server in the gRPC metadata.
+// The default is to use the global tracer provider.
+func WithStorageTracerProvider(tp trace.TracerProvider) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithStorageMeterProvider sets the OpenTelemetry meter provider used to record gRPC call metrics
+// The default is to use the global meter provider.
+func WithStorageMeterProvider(mp metric.MeterProvider) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.meterProvider = mp
+	}
+}
+
 // storageClientOptionsWithDefaults sets defaults for the Storage client and applies overrides
 func storageClientOptionsWithDefaults(opts []StorageClientOption) *StorageClientOptions {
 	defaultCallTimeout := 30 * time.Second
@@ -57,12 +91,15 @@ func storageClientOptionsWithDefaults(opts []StorageClientOption) *StorageClient
 		withTrace:   false,
 		hostType:    "",
 		hostID:      "",
+		batchSize:   DefaultStorageBatchSize,
 	}
 
 	for _, apply := range opts {
 		apply(options)
 	}
 
+	options.telemetry = newTelemetry("storage.client", options.tracerProvider, options.meterProvider)
+
 	return options
 }
 
@@ -75,6 +112,10 @@ type ProfileOption func(*ProfileOptions)
 type ProfileOptions struct {
 	Region                 string
 	CloudAccountIdentifier string
+	PageSize               int64
+	LabelSelector          string
+	FieldSelector          string
+	AllNamespaces          bool
 }
 
 // WithProfileRegion sets the region for non-k8s scoped resources
@@ -91,6 +132,36 @@ func WithProfileCloudAccountIdentifier(cloudAccountIdentifier string) ProfileOpt
 	}
 }
 
+// WithProfilePageSize sets the number of profiles requested per page by the list iterators and ListAll helpers
+// The default (0) lets the server choose.
+func WithProfilePageSize(pageSize int64) ProfileOption {
+	return func(o *ProfileOptions) {
+		o.PageSize = pageSize
+	}
+}
+
+// WithProfileLabelSelector restricts profile lists to the profiles matching a Kubernetes label selector, e.g. "app=nginx"
+func WithProfileLabelSelector(selector string) ProfileOption {
+	return func(o *ProfileOptions) {
+		o.LabelSelector = selector
+	}
+}
+
+// WithProfileFieldSelector restricts profile lists to the profiles matching a Kubernetes field selector, e.g. "workloadKind=Deployment"
+// The supported fields are the ProfileField constants.
+func WithProfileFieldSelector(selector string) ProfileOption {
+	return func(o *ProfileOptions) {
+		o.FieldSelector = selector
+	}
+}
+
+// WithProfileAllNamespaces lists the profiles of all namespaces; the namespace given to the list must be empty
+func WithProfileAllNamespaces() ProfileOption {
+	return func(o *ProfileOptions) {
+		o.AllNamespaces = true
+	}
+}
+
 // profileOptionsWithDefaults applies profile query options
 func profileOptionsWithDefaults(opts []ProfileOption) *ProfileOptions {
 	options := &ProfileOptions{
@@ -104,3 +175,55 @@ func profileOptionsWithDefaults(opts []ProfileOption) *ProfileOptions {
 
 	return options
 }
+
+// ========== Watch Options ==========
+
+// WatchOption allows to configure profile watches
+type WatchOption func(*WatchOptions)
+
+// WatchOptions holds configuration for profile watches
+type WatchOptions struct {
+	ResourceVersion        string
+	AllowBookmarks         bool
+	Region                 string
+	CloudAccountIdentifier string
+}
+
+// WithWatchResourceVersion starts the watch after the given resource version, e.g. the one of a previous list
+func WithWatchResourceVersion(resourceVersion string) WatchOption {
+	return func(o *WatchOptions) {
+		o.ResourceVersion = resourceVersion
+	}
+}
+
+// WithWatchBookmarks requests bookmark events, carrying the latest resource version
+func WithWatchBookmarks() WatchOption {
+	return func(o *WatchOptions) {
+		o.AllowBookmarks = true
+	}
+}
+
+// WithWatchRegion sets the region for non-k8s scoped resources
+func WithWatchRegion(region string) WatchOption {
+	return func(o *WatchOptions) {
+		o.Region = region
+	}
+}
+
+// WithWatchCloudAccountIdentifier sets the cloud account identifier for non-k8s scoped resources (e.g. AWS account ID, GCP project ID)
+func WithWatchCloudAccountIdentifier(cloudAccountIdentifier string) WatchOption {
+	return func(o *WatchOptions) {
+		o.CloudAccountIdentifier = cloudAccountIdentifier
+	}
+}
+
+// watchOptionsWithDefaults applies profile watch options
+func watchOptionsWithDefaults(opts []WatchOption) *WatchOptions {
+	options := &WatchOptions{}
+
+	for _, apply := range opts {
+		apply(options)
+	}
+
+	return options
+}
@@ -0,0 +1,257 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	_ "google.golang.org/grpc/health" // registers the client-side health checking
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+)
+
+// ErrStorageNotServing is returned by Health when the storage server reports that it is not serving
+var ErrStorageNotServing = errors.New("storage server is not serving")
+
+// StorageRetryPolicy configures the retries of failed gRPC calls, performed by the gRPC library
+// Only the idempotent calls (GetProfile, List* and WatchProfiles) are retried: profiles sent, streamed or deleted
+// are not, since a call which failed after reaching the server could be applied twice.
+// Zero values are replaced by defaults.
+type StorageRetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the original call. gRPC caps it to 5. Defaults to 4.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. Defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Defaults to 5s.
+	MaxBackoff time.Duration
+	// BackoffMultiplier grows the delay after each retry. Defaults to 2.
+	BackoffMultiplier float64
+	// RetryableStatusCodes are the status codes which are retried. Defaults to Unavailable.
+	RetryableStatusCodes []codes.Code
+}
+
+// WithStorageKeepalive sends keepalive pings on the connection to the storage server, even when no call is active
+// This detects connections silently dropped by NAT gateways or load balancers.
+// interval is the delay without activity after which a ping is sent, and timeout the delay after which the connection
+// is closed if the ping is not acknowledged. The server must allow pings at this interval (see its keepalive enforcement policy).
+// The default is to send no keepalive pings.
+func WithStorageKeepalive(interval, timeout time.Duration) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.keepalive = &keepalive.ClientParameters{
+			Time:                interval,
+			Timeout:             timeout,
+			PermitWithoutStream: true,
+		}
+	}
+}
+
+// WithStorageRetryPolicy retries failed idempotent gRPC calls to the storage server (see StorageRetryPolicy)
+// The default is to not retry calls.
+func WithStorageRetryPolicy(policy StorageRetryPolicy) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithStorageConnectBackoff sets the backoff between attempts to reconnect to the storage server
+// The defaults of gRPC are a base delay of 1s and a maximum delay of 120s.
+func WithStorageConnectBackoff(baseDelay, maxDelay time.Duration) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		config := backoff.DefaultConfig
+		config.BaseDelay = baseDelay
+		config.MaxDelay = maxDelay
+		o.connectBackoff = &config
+	}
+}
+
+// WithStorageHealthCheck enables client-side health checking with the standard gRPC health protocol
+// The connection is only used while the server reports the service as serving, and the connection state reflects the health of the server.
+// serviceName is also the service checked by Health.
+// An empty service name stands for the overall health of the server.
+//
+// Health checking switches the load-balancing policy from pick_first to round_robin, as pick_first ignores health checks:
+// the client then connects to every address resolved for the server, and spreads calls across the healthy ones.
+// A service config supplied by the name resolver (e.g. a DNS TXT record) still takes precedence.
+func WithStorageHealthCheck(serviceName string) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.healthCheck = true
+		o.healthService = serviceName
+	}
+}
+
+// dialOptions yields the dial options configuring the connection: keepalive, backoff and service config
+func (o *StorageClientOptions) dialOptions() ([]grpc.DialOption, error) {
+	var dialOpts []grpc.DialOption
+
+	if o.keepalive != nil {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(*o.keepalive))
+	}
+
+	if o.connectBackoff != nil {
+		dialOpts = append(dialOpts, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           *o.connectBackoff,
+			MinConnectTimeout: 20 * time.Second,
+		}))
+	}
+
+	if o.retryPolicy != nil || o.healthCheck {
+		serviceConfig, err := o.serviceConfig()
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithDefaultServiceConfig(serviceConfig))
+	}
+
+	return dialOpts, nil
+}
+
+// idempotentStorageMethods are the methods of the storage service which are safe to retry
+var idempotentStorageMethods = []string{
+	"GetProfile",
+	"ListApplicationProfiles",
+	"ListNetworkNeighborhoods",
+	"WatchProfiles",
+}
+
+// serviceConfig builds the gRPC service config (JSON) holding the retry policy and the health checking settings
+func (o *StorageClientOptions) serviceConfig() (string, error) {
+	type retryPolicy struct {
+		MaxAttempts          int          `json:"maxAttempts"`
+		InitialBackoff       string       `json:"initialBackoff"`
+		MaxBackoff           string       `json:"maxBackoff"`
+		BackoffMultiplier    float64      `json:"backoffMultiplier"`
+		RetryableStatusCodes []codes.Code `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []map[string]string `json:"name"`
+		RetryPolicy *retryPolicy        `json:"retryPolicy,omitempty"`
+	}
+	type healthCheckConfig struct {
+		ServiceName string `json:"serviceName"`
+	}
+	var config struct {
+		LoadBalancingConfig []map[string]any   `json:"loadBalancingConfig,omitempty"`
+		MethodConfig        []methodConfig     `json:"methodConfig,omitempty"`
+		HealthCheckConfig   *healthCheckConfig `json:"healthCheckConfig,omitempty"`
+	}
+
+	if o.retryPolicy != nil {
+		policy := retryPolicy{
+			MaxAttempts:          o.retryPolicy.MaxAttempts,
+			InitialBackoff:       durationJSON(o.retryPolicy.InitialBackoff, 100*time.Millisecond),
+			MaxBackoff:           durationJSON(o.retryPolicy.MaxBackoff, 5*time.Second),
+			BackoffMultiplier:    o.retryPolicy.BackoffMultiplier,
+			RetryableStatusCodes: o.retryPolicy.RetryableStatusCodes,
+		}
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = 4
+		}
+		if policy.BackoffMultiplier <= 0 {
+			policy.BackoffMultiplier = 2
+		}
+		if len(policy.RetryableStatusCodes) == 0 {
+			policy.RetryableStatusCodes = []codes.Code{codes.Unavailable}
+		}
+
+		names := make([]map[string]string, 0, len(idempotentStorageMethods))
+		for _, method := range idempotentStorageMethods {
+			names = append(names, map[string]string{"service": proto.StorageService_ServiceDesc.ServiceName, "method": method})
+		}
+
+		config.MethodConfig = []methodConfig{{
+			Name:        names,
+			RetryPolicy: &policy,
+		}}
+	}
+
+	if o.healthCheck {
+		// the default pick_first policy ignores health checking (see WithStorageHealthCheck)
+		config.LoadBalancingConfig = []map[string]any{{"round_robin": struct{}{}}}
+		config.HealthCheckConfig = &healthCheckConfig{ServiceName: o.healthService}
+	}
+
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("invalid service config: %w", err)
+	}
+
+	return string(buf), nil
+}
+
+// durationJSON formats a duration as expected by the service config, e.g. "0.1s"
+func durationJSON(d, defaultValue time.Duration) string {
+	if d <= 0 {
+		d = defaultValue
+	}
+
+	return fmt.Sprintf("%.9fs", d.Seconds())
+}
+
+// State returns the live state of the connection to the storage server
+// The state is connectivity.Shutdown when the client is not connected.
+func (c *StorageClient) State() connectivity.State {
+	if c.conn == nil {
+		return connectivity.Shutdown
+	}
+
+	return c.conn.GetState()
+}
+
+// WaitForReady blocks until the connection to the storage server is ready, or the context is done
+// An idle connection is asked to connect.
+func (c *StorageClient) WaitForReady(ctx context.Context) error {
+	conn := c.conn
+	if conn == nil {
+		return fmt.Errorf("client is not connected")
+	}
+
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			conn.Connect()
+		case connectivity.Shutdown:
+			return fmt.Errorf("client is closed")
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("storage server is not ready (%s): %w", state, ctx.Err())
+		}
+	}
+}
+
+// Health checks the health of the storage server with the standard gRPC health protocol
+// It returns ErrStorageNotServing if the server reports that it is not serving.
+func (c *StorageClient) Health(ctx context.Context) error {
+	if c.conn == nil {
+		return fmt.Errorf("client is not connected")
+	}
+
+	ctx = c.withMetadata(ctx)
+
+	if c.callTimeout != nil && *c.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *c.callTimeout)
+		defer cancel()
+	}
+
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{Service: c.healthService})
+	if err != nil {
+		return fmt.Errorf("failed to check storage server health: %w", err)
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%w: %s", ErrStorageNotServing, resp.GetStatus())
+	}
+
+	return nil
+}
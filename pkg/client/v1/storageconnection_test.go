@@ -0,0 +1,189 @@
+package v1
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// flakyStorageServer fails the first calls to SendContainerProfile and GetProfile with Unavailable
+type flakyStorageServer struct {
+	proto.UnimplementedStorageServiceServer
+
+	failures int64
+	calls    atomic.Int64
+}
+
+func (s *flakyStorageServer) SendContainerProfile(context.Context, *proto.SendContainerProfileRequest) (*proto.SendContainerProfileResponse, error) {
+	if s.calls.Add(1) <= s.failures {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+
+	return &proto.SendContainerProfileResponse{Success: true}, nil
+}
+
+func (s *flakyStorageServer) GetProfile(context.Context, *proto.GetProfileRequest) (*proto.GetProfileResponse, error) {
+	if s.calls.Add(1) <= s.failures {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+
+	return &proto.GetProfileResponse{Success: true}, nil
+}
+
+// startStorageServer starts an insecure storage server, with the standard health service, and yields its URL
+func startStorageServer(t testing.TB, srv proto.StorageServiceServer) (string, *health.Server) {
+	server := grpc.NewServer()
+	proto.RegisterStorageServiceServer(server, srv)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return "grpc://" + lis.Addr().String(), healthServer
+}
+
+func TestStorageClientHealth(t *testing.T) {
+	t.Parallel()
+
+	grpcURL, healthServer := startStorageServer(t, &flakyStorageServer{})
+
+	client, err := NewStorageClient(grpcURL, "account", "key", "cluster",
+		WithStorageKeepalive(30*time.Second, 10*time.Second),
+		WithStorageConnectBackoff(100*time.Millisecond, time.Second),
+	)
+	require.NoError(t, err)
+	require.Equal(t, connectivity.Shutdown, client.State())
+	require.Error(t, client.Health(context.Background()))
+	require.Error(t, client.WaitForReady(context.Background()))
+
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("should wait for the connection to be ready", func(t *testing.T) {
+		require.NoError(t, client.WaitForReady(ctx))
+		require.Equal(t, connectivity.Ready, client.State())
+	})
+
+	t.Run("should report a serving server", func(t *testing.T) {
+		require.NoError(t, client.Health(ctx))
+	})
+
+	t.Run("should report a server which is not serving", func(t *testing.T) {
+		healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		t.Cleanup(func() { healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING) })
+
+		require.ErrorIs(t, client.Health(ctx), ErrStorageNotServing)
+	})
+
+	t.Run("should reflect the health of the server in the connection state", func(t *testing.T) {
+		checked, err := NewStorageClient(grpcURL, "account", "key", "cluster", WithStorageHealthCheck(""))
+		require.NoError(t, err)
+		require.NoError(t, checked.Connect())
+		t.Cleanup(func() { _ = checked.Close() })
+		require.NoError(t, checked.WaitForReady(ctx))
+
+		healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		t.Cleanup(func() { healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING) })
+
+		require.Eventually(t, func() bool {
+			return checked.State() == connectivity.TransientFailure
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestStorageClientWaitForReadyTimeout(t *testing.T) {
+	t.Parallel()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	client, err := NewStorageClient("grpc://"+addr, "account", "key", "cluster")
+	require.NoError(t, err)
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, client.WaitForReady(ctx), context.DeadlineExceeded)
+	assert.NotEqual(t, connectivity.Ready, client.State())
+}
+
+func TestStorageClientRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	connect := func(t *testing.T, srv *flakyStorageServer, opts ...StorageClientOption) *StorageClient {
+		grpcURL, _ := startStorageServer(t, srv)
+
+		client, err := NewStorageClient(grpcURL, "account", "key", "cluster", opts...)
+		require.NoError(t, err)
+		require.NoError(t, client.Connect())
+		t.Cleanup(func() { _ = client.Close() })
+
+		return client
+	}
+	get := func(t *testing.T, srv *flakyStorageServer, opts ...StorageClientOption) error {
+		_, err := connect(t, srv, opts...).GetApplicationProfile(context.Background(), "namespace", "name")
+
+		return err
+	}
+
+	t.Run("should retry unavailable calls", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &flakyStorageServer{failures: 2}
+		require.NoError(t, get(t, srv,
+			WithStorageRetryPolicy(StorageRetryPolicy{InitialBackoff: 10 * time.Millisecond}),
+			WithStorageHealthCheck(""),
+		))
+		require.Equal(t, int64(3), srv.calls.Load())
+	})
+
+	t.Run("should give up after the maximum attempts", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &flakyStorageServer{failures: 5}
+		err := get(t, srv, WithStorageRetryPolicy(StorageRetryPolicy{MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond}))
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, int64(2), srv.calls.Load())
+	})
+
+	t.Run("should not retry by default", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &flakyStorageServer{failures: 1}
+		require.Equal(t, codes.Unavailable, status.Code(get(t, srv)))
+		require.Equal(t, int64(1), srv.calls.Load())
+	})
+
+	t.Run("should not retry calls which are not idempotent", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &flakyStorageServer{failures: 1}
+		client := connect(t, srv, WithStorageRetryPolicy(StorageRetryPolicy{InitialBackoff: 10 * time.Millisecond}))
+		_, err := client.SendContainerProfile(context.Background(), &v1beta1.ContainerProfile{})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, int64(1), srv.calls.Load())
+	})
+}
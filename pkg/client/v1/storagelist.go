@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Fields supported by the field selectors of profile lists, see WithProfileFieldSelector
+const (
+	ProfileFieldName         = "metadata.name"
+	ProfileFieldNamespace    = "metadata.namespace"
+	ProfileFieldWorkloadKind = "workloadKind" // kubescape.io/workload-kind label, e.g. "Deployment"
+	ProfileFieldStatus       = "status"       // kubescape.io/status annotation, e.g. "learning" or "completed"
+	ProfileFieldCompletion   = "completion"   // kubescape.io/completion annotation, "complete" or "partial"
+)
+
+// ErrRepeatedContinueToken is returned when the storage server returns a continue token twice, which would page forever
+var ErrRepeatedContinueToken = errors.New("storage server returned a continue token twice")
+
+// IterApplicationProfiles iterates over the ApplicationProfiles in a namespace (metadata only, nil Spec), fetching the pages as needed
+// The page size is set with WithProfilePageSize. Iteration stops after yielding an error.
+func (c *StorageClient) IterApplicationProfiles(ctx context.Context, namespace string, opts ...ProfileOption) iter.Seq2[*v1beta1.ApplicationProfile, error] {
+	return paginate(ctx, opts, func(ctx context.Context, limit int64, cont string) ([]v1beta1.ApplicationProfile, string, error) {
+		list, err := c.ListApplicationProfiles(ctx, namespace, limit, cont, opts...)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return list.Items, list.Continue, nil
+	})
+}
+
+// IterNetworkNeighborhoods iterates over the NetworkNeighborhoods in a namespace (metadata only, nil Spec), fetching the pages as needed
+// The page size is set with WithProfilePageSize. Iteration stops after yielding an error.
+func (c *StorageClient) IterNetworkNeighborhoods(ctx context.Context, namespace string, opts ...ProfileOption) iter.Seq2[*v1beta1.NetworkNeighborhood, error] {
+	return paginate(ctx, opts, func(ctx context.Context, limit int64, cont string) ([]v1beta1.NetworkNeighborhood, string, error) {
+		list, err := c.ListNetworkNeighborhoods(ctx, namespace, limit, cont, opts...)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return list.Items, list.Continue, nil
+	})
+}
+
+// ListAllApplicationProfiles lists the ApplicationProfiles of all the pages in a namespace (metadata only, nil Spec)
+func (c *StorageClient) ListAllApplicationProfiles(ctx context.Context, namespace string, opts ...ProfileOption) (*v1beta1.ApplicationProfileList, error) {
+	list := &v1beta1.ApplicationProfileList{}
+	for profile, err := range c.IterApplicationProfiles(ctx, namespace, opts...) {
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, *profile)
+	}
+
+	return list, nil
+}
+
+// ListAllNetworkNeighborhoods lists the NetworkNeighborhoods of all the pages in a namespace (metadata only, nil Spec)
+func (c *StorageClient) ListAllNetworkNeighborhoods(ctx context.Context, namespace string, opts ...ProfileOption) (*v1beta1.NetworkNeighborhoodList, error) {
+	list := &v1beta1.NetworkNeighborhoodList{}
+	for neighborhood, err := range c.IterNetworkNeighborhoods(ctx, namespace, opts...) {
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, *neighborhood)
+	}
+
+	return list, nil
+}
+
+// paginate iterates over the items of the pages returned by list, following the continue tokens
+func paginate[T any](ctx context.Context, opts []ProfileOption, list func(ctx context.Context, limit int64, cont string) ([]T, string, error)) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		limit := profileOptionsWithDefaults(opts).PageSize
+		seen := map[string]struct{}{}
+		cont := ""
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			items, next, err := list(ctx, limit, cont)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range items {
+				if !yield(&items[i], nil) {
+					return
+				}
+			}
+
+			if next == "" {
+				return
+			}
+
+			if _, ok := seen[next]; ok {
+				yield(nil, fmt.Errorf("%w: %q", ErrRepeatedContinueToken, next))
+				return
+			}
+			seen[next] = struct{}{}
+			cont = next
+		}
+	}
+}
+
+// validateList checks the selectors and the namespace of a profile list, before calling the server
+func (o *ProfileOptions) validateList(namespace string) error {
+	if o.AllNamespaces && namespace != "" {
+		return fmt.Errorf("namespace must be empty when listing all namespaces, got %q", namespace)
+	}
+
+	if o.LabelSelector != "" {
+		if _, err := labels.Parse(o.LabelSelector); err != nil {
+			return fmt.Errorf("invalid label selector: %w", err)
+		}
+	}
+
+	if o.FieldSelector != "" {
+		if _, err := fields.ParseSelector(o.FieldSelector); err != nil {
+			return fmt.Errorf("invalid field selector: %w", err)
+		}
+	}
+
+	return nil
+}
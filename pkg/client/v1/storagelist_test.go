@@ -0,0 +1,211 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pagedApplicationProfiles serves the pages of ApplicationProfiles, keyed by continue token, and records the requests
+func pagedApplicationProfiles(pages map[string]*proto.ListApplicationProfilesResponse, requests *[]*proto.ListApplicationProfilesRequest) *mockStorageServiceClient {
+	return &mockStorageServiceClient{
+		listApplicationProfilesFunc: func(ctx context.Context, in *proto.ListApplicationProfilesRequest, opts ...grpc.CallOption) (*proto.ListApplicationProfilesResponse, error) {
+			*requests = append(*requests, in)
+			return pages[in.Cont], nil
+		},
+	}
+}
+
+func applicationProfilesPage(cont string, names ...string) *proto.ListApplicationProfilesResponse {
+	resp := &proto.ListApplicationProfilesResponse{Success: true, Cont: cont}
+	for _, name := range names {
+		resp.ApplicationProfiles = append(resp.ApplicationProfiles, &v1beta1.ApplicationProfile{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	return resp
+}
+
+func TestStorageClient_IterApplicationProfiles(t *testing.T) {
+	client, err := NewStorageClient("grpc://storage.example.com:50051", "test-account", "test-key", "test-cluster")
+	require.NoError(t, err)
+
+	pages := map[string]*proto.ListApplicationProfilesResponse{
+		"":       applicationProfilesPage("page-2", "a", "b"),
+		"page-2": applicationProfilesPage("page-3", "c", "d"),
+		"page-3": applicationProfilesPage("", "e"),
+	}
+
+	t.Run("should page transparently", func(t *testing.T) {
+		var requests []*proto.ListApplicationProfilesRequest
+		client.protoClient = pagedApplicationProfiles(pages, &requests)
+
+		var names []string
+		for profile, err := range client.IterApplicationProfiles(context.Background(), "default", WithProfilePageSize(2), WithProfileRegion("us-east-1")) {
+			require.NoError(t, err)
+			names = append(names, profile.Name)
+		}
+
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+		require.Len(t, requests, 3)
+		for i, cont := range []string{"", "page-2", "page-3"} {
+			assert.Equal(t, cont, requests[i].Cont)
+			assert.Equal(t, int64(2), requests[i].Limit)
+			assert.Equal(t, "default", requests[i].Namespace)
+			assert.Equal(t, "us-east-1", requests[i].Region)
+		}
+	})
+
+	t.Run("should stop fetching pages when the loop breaks", func(t *testing.T) {
+		var requests []*proto.ListApplicationProfilesRequest
+		client.protoClient = pagedApplicationProfiles(pages, &requests)
+
+		for profile, err := range client.IterApplicationProfiles(context.Background(), "default") {
+			require.NoError(t, err)
+			if profile.Name == "b" {
+				break
+			}
+		}
+
+		assert.Len(t, requests, 1)
+	})
+
+	t.Run("should detect a repeated continue token", func(t *testing.T) {
+		var requests []*proto.ListApplicationProfilesRequest
+		client.protoClient = pagedApplicationProfiles(map[string]*proto.ListApplicationProfilesResponse{
+			"":     applicationProfilesPage("loop", "a"),
+			"loop": applicationProfilesPage("loop", "b"),
+		}, &requests)
+
+		list, err := client.ListAllApplicationProfiles(context.Background(), "default")
+		require.ErrorIs(t, err, ErrRepeatedContinueToken)
+		assert.Nil(t, list)
+		assert.Len(t, requests, 2)
+	})
+
+	t.Run("should respect the context cancellation", func(t *testing.T) {
+		var requests []*proto.ListApplicationProfilesRequest
+		client.protoClient = pagedApplicationProfiles(pages, &requests)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var names []string
+		var iterErr error
+		for profile, err := range client.IterApplicationProfiles(ctx, "default") {
+			if err != nil {
+				iterErr = err
+				break
+			}
+			names = append(names, profile.Name)
+			cancel()
+		}
+
+		require.ErrorIs(t, iterErr, context.Canceled)
+		assert.Equal(t, []string{"a", "b"}, names)
+		assert.Len(t, requests, 1)
+	})
+
+	t.Run("should report a failed page", func(t *testing.T) {
+		var requests []*proto.ListApplicationProfilesRequest
+		client.protoClient = pagedApplicationProfiles(map[string]*proto.ListApplicationProfilesResponse{
+			"":       applicationProfilesPage("page-2", "a"),
+			"page-2": {ErrorMessage: "boom", ErrorCode: proto.ErrorCode_ERROR_CODE_INTERNAL_ERROR},
+		}, &requests)
+
+		_, err := client.ListAllApplicationProfiles(context.Background(), "default")
+		require.ErrorContains(t, err, "boom")
+	})
+}
+
+func TestStorageClient_ListAllNetworkNeighborhoods(t *testing.T) {
+	client, err := NewStorageClient("grpc://storage.example.com:50051", "test-account", "test-key", "test-cluster")
+	require.NoError(t, err)
+
+	var conts []string
+	client.protoClient = &mockStorageServiceClient{
+		listNetworkNeighborhoodsFunc: func(ctx context.Context, in *proto.ListNetworkNeighborhoodsRequest, opts ...grpc.CallOption) (*proto.ListNetworkNeighborhoodsResponse, error) {
+			conts = append(conts, in.Cont)
+			assert.Equal(t, int64(1), in.Limit)
+			if in.Cont == "" {
+				return &proto.ListNetworkNeighborhoodsResponse{
+					Success:              true,
+					NetworkNeighborhoods: []*v1beta1.NetworkNeighborhood{{ObjectMeta: metav1.ObjectMeta{Name: "a"}}},
+					Cont:                 "next",
+				}, nil
+			}
+			return &proto.ListNetworkNeighborhoodsResponse{
+				Success:              true,
+				NetworkNeighborhoods: []*v1beta1.NetworkNeighborhood{{ObjectMeta: metav1.ObjectMeta{Name: "b"}}},
+			}, nil
+		},
+	}
+
+	list, err := client.ListAllNetworkNeighborhoods(context.Background(), "kube-system", WithProfilePageSize(1))
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "a", list.Items[0].Name)
+	assert.Equal(t, "b", list.Items[1].Name)
+	assert.Empty(t, list.Continue)
+	assert.Equal(t, []string{"", "next"}, conts)
+}
+
+func TestStorageClient_ListSelectors(t *testing.T) {
+	client, err := NewStorageClient("grpc://storage.example.com:50051", "test-account", "test-key", "test-cluster")
+	require.NoError(t, err)
+
+	opts := []ProfileOption{
+		WithProfileAllNamespaces(),
+		WithProfileLabelSelector("app=nginx,tier in (web)"),
+		WithProfileFieldSelector(ProfileFieldWorkloadKind + "=Deployment," + ProfileFieldStatus + "!=completed"),
+	}
+
+	var calls int
+	client.protoClient = &mockStorageServiceClient{
+		listApplicationProfilesFunc: func(ctx context.Context, in *proto.ListApplicationProfilesRequest, opts ...grpc.CallOption) (*proto.ListApplicationProfilesResponse, error) {
+			calls++
+			assert.Empty(t, in.Namespace)
+			assert.True(t, in.AllNamespaces)
+			assert.Equal(t, "app=nginx,tier in (web)", in.LabelSelector)
+			assert.Equal(t, "workloadKind=Deployment,status!=completed", in.FieldSelector)
+			return &proto.ListApplicationProfilesResponse{Success: true}, nil
+		},
+		listNetworkNeighborhoodsFunc: func(ctx context.Context, in *proto.ListNetworkNeighborhoodsRequest, opts ...grpc.CallOption) (*proto.ListNetworkNeighborhoodsResponse, error) {
+			calls++
+			assert.Empty(t, in.Namespace)
+			assert.True(t, in.AllNamespaces)
+			assert.Equal(t, "app=nginx,tier in (web)", in.LabelSelector)
+			assert.Equal(t, "workloadKind=Deployment,status!=completed", in.FieldSelector)
+			return &proto.ListNetworkNeighborhoodsResponse{Success: true}, nil
+		},
+	}
+
+	t.Run("should send the selectors and the namespace mode", func(t *testing.T) {
+		_, err := client.ListApplicationProfiles(context.Background(), "", 10, "", opts...)
+		require.NoError(t, err)
+
+		_, err = client.ListAllNetworkNeighborhoods(context.Background(), "", opts...)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should reject invalid lists before calling the server", func(t *testing.T) {
+		calls = 0
+
+		_, err := client.ListApplicationProfiles(context.Background(), "default", 10, "", WithProfileAllNamespaces())
+		require.ErrorContains(t, err, "namespace must be empty")
+
+		_, err = client.ListNetworkNeighborhoods(context.Background(), "default", 10, "", WithProfileLabelSelector("app in nginx"))
+		require.ErrorContains(t, err, "invalid label selector")
+
+		_, err = client.ListAllApplicationProfiles(context.Background(), "default", WithProfileFieldSelector("status"))
+		require.ErrorContains(t, err, "invalid field selector")
+
+		assert.Zero(t, calls)
+	})
+}
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultStorageBatchSize is the default number of container profiles sent per stream by SendContainerProfiles
+const DefaultStorageBatchSize = 100
+
+// WithStorageBatchSize sets the maximum number of container profiles sent per stream by SendContainerProfiles
+// The default is DefaultStorageBatchSize.
+func WithStorageBatchSize(size int) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// SendContainerProfiles sends container profiles to the storage server, streamed in batches
+//
+// It returns one acknowledgement per profile, in the order of the profiles: a profile which fails
+// is reported in its acknowledgement, without failing the others.
+// An error is returned if a batch cannot be sent at all: the profiles which were not acknowledged
+// are then reported as failed, with the error message.
+//
+// Profiles are sent one by one with SendContainerProfile to servers which do not support streaming.
+func (c *StorageClient) SendContainerProfiles(ctx context.Context, profiles []*v1beta1.ContainerProfile) ([]*proto.ContainerProfileAck, error) {
+	if c.protoClient == nil {
+		return nil, fmt.Errorf("client is not connected")
+	}
+
+	acks := make([]*proto.ContainerProfileAck, len(profiles))
+
+	for start := 0; start < len(profiles); start += c.batchSize {
+		end := min(start+c.batchSize, len(profiles))
+
+		var err error
+		if c.streamingUnsupported.Load() {
+			err = c.sendContainerProfilesUnary(ctx, start, profiles[start:end], acks[start:end])
+		} else {
+			err = c.streamContainerProfiles(ctx, start, profiles[start:end], acks[start:end])
+			if status.Code(err) == codes.Unimplemented {
+				// older server: fall back to unary calls, and stop trying to stream
+				c.streamingUnsupported.Store(true)
+				err = c.sendContainerProfilesUnary(ctx, start, profiles[start:end], acks[start:end])
+			}
+		}
+
+		if err != nil {
+			for i := start; i < len(acks); i++ {
+				if acks[i] == nil {
+					acks[i] = &proto.ContainerProfileAck{Index: int64(i), ErrorMessage: err.Error()}
+				}
+			}
+
+			return acks, fmt.Errorf("failed to send container profiles: %w", err)
+		}
+	}
+
+	return acks, nil
+}
+
+// streamContainerProfiles sends a batch of container profiles on a single stream
+// offset is the index of the first profile of the batch.
+func (c *StorageClient) streamContainerProfiles(ctx context.Context, offset int, profiles []*v1beta1.ContainerProfile, acks []*proto.ContainerProfileAck) error {
+	ctx = c.withMetadata(ctx)
+
+	if c.callTimeout != nil && *c.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *c.callTimeout)
+		defer cancel()
+	}
+
+	stream, err := c.protoClient.StreamContainerProfiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, profile := range profiles {
+		if err := stream.Send(&proto.StreamContainerProfilesRequest{ContainerProfile: profile, Index: int64(i)}); err != nil {
+			if errors.Is(err, io.EOF) {
+				// the server ended the stream: its status is returned by CloseAndRecv
+				break
+			}
+
+			return err
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	for _, ack := range resp.GetAcks() {
+		if i := ack.GetIndex(); i >= 0 && i < int64(len(acks)) {
+			acks[i] = ack
+		}
+	}
+
+	for i := range acks {
+		if acks[i] == nil {
+			// not acknowledged individually: the outcome of the stream applies
+			acks[i] = &proto.ContainerProfileAck{
+				Success:      resp.GetSuccess(),
+				ErrorMessage: resp.GetErrorMessage(),
+				ErrorCode:    resp.GetErrorCode(),
+			}
+		}
+		acks[i].Index = int64(offset + i)
+	}
+
+	return nil
+}
+
+// sendContainerProfilesUnary sends a batch of container profiles one by one
+// offset is the index of the first profile of the batch.
+func (c *StorageClient) sendContainerProfilesUnary(ctx context.Context, offset int, profiles []*v1beta1.ContainerProfile, acks []*proto.ContainerProfileAck) error {
+	for i, profile := range profiles {
+		resp, err := c.SendContainerProfile(ctx, profile)
+		if err != nil {
+			return err
+		}
+
+		acks[i] = &proto.ContainerProfileAck{
+			Index:        int64(offset + i),
+			Success:      resp.GetSuccess(),
+			ErrorMessage: resp.GetErrorMessage(),
+			ErrorCode:    resp.GetErrorCode(),
+		}
+	}
+
+	return nil
+}
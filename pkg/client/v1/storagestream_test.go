@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// streamingStorageServer stores container profiles, and rejects the profiles named "invalid"
+type streamingStorageServer struct {
+	proto.UnimplementedStorageServiceServer
+
+	mu      sync.Mutex
+	streams int
+	names   []string
+}
+
+func (s *streamingStorageServer) StreamContainerProfiles(stream grpc.ClientStreamingServer[proto.StreamContainerProfilesRequest, proto.StreamContainerProfilesResponse]) error {
+	s.mu.Lock()
+	s.streams++
+	s.mu.Unlock()
+
+	resp := &proto.StreamContainerProfilesResponse{Success: true}
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(resp)
+		}
+		if err != nil {
+			return err
+		}
+
+		ack := &proto.ContainerProfileAck{Index: req.GetIndex(), Success: true}
+		if name := req.GetContainerProfile().GetName(); name == "invalid" {
+			ack = &proto.ContainerProfileAck{Index: req.GetIndex(), ErrorMessage: "invalid profile", ErrorCode: proto.ErrorCode_ERROR_CODE_INVALID_REQUEST}
+			resp.Success = false
+		} else {
+			s.mu.Lock()
+			s.names = append(s.names, name)
+			s.mu.Unlock()
+		}
+		resp.Acks = append(resp.Acks, ack)
+	}
+}
+
+// unaryStorageServer only supports the unary upload of container profiles, like older servers
+type unaryStorageServer struct {
+	proto.UnimplementedStorageServiceServer
+
+	mu    sync.Mutex
+	names []string
+}
+
+func (s *unaryStorageServer) SendContainerProfile(_ context.Context, req *proto.SendContainerProfileRequest) (*proto.SendContainerProfileResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, req.GetContainerProfile().GetName())
+
+	return &proto.SendContainerProfileResponse{Success: true}, nil
+}
+
+func testProfiles(names ...string) []*v1beta1.ContainerProfile {
+	profiles := make([]*v1beta1.ContainerProfile, 0, len(names))
+	for _, name := range names {
+		profiles = append(profiles, &v1beta1.ContainerProfile{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	return profiles
+}
+
+func TestStorageClient_SendContainerProfiles(t *testing.T) {
+	t.Parallel()
+
+	connect := func(t *testing.T, srv proto.StorageServiceServer, opts ...StorageClientOption) *StorageClient {
+		grpcURL, _ := startStorageServer(t, srv)
+
+		client, err := NewStorageClient(grpcURL, "account", "key", "cluster", opts...)
+		require.NoError(t, err)
+		require.NoError(t, client.Connect())
+		t.Cleanup(func() { _ = client.Close() })
+
+		return client
+	}
+
+	t.Run("should stream profiles in batches", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &streamingStorageServer{}
+		client := connect(t, srv, WithStorageBatchSize(2))
+
+		acks, err := client.SendContainerProfiles(context.Background(), testProfiles("a", "b", "c", "d", "e"))
+		require.NoError(t, err)
+		require.Len(t, acks, 5)
+		for i, ack := range acks {
+			assert.Equal(t, int64(i), ack.GetIndex())
+			assert.True(t, ack.GetSuccess())
+		}
+		assert.Equal(t, 3, srv.streams)
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, srv.names)
+	})
+
+	t.Run("should report partial failures per profile", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &streamingStorageServer{}
+		client := connect(t, srv)
+
+		acks, err := client.SendContainerProfiles(context.Background(), testProfiles("a", "invalid", "c"))
+		require.NoError(t, err)
+		require.Len(t, acks, 3)
+		assert.True(t, acks[0].GetSuccess())
+		assert.False(t, acks[1].GetSuccess())
+		assert.Equal(t, int64(1), acks[1].GetIndex())
+		assert.Equal(t, "invalid profile", acks[1].GetErrorMessage())
+		assert.Equal(t, proto.ErrorCode_ERROR_CODE_INVALID_REQUEST, acks[1].GetErrorCode())
+		assert.True(t, acks[2].GetSuccess())
+		assert.Equal(t, []string{"a", "c"}, srv.names)
+	})
+
+	t.Run("should fall back to unary calls against older servers", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &unaryStorageServer{}
+		client := connect(t, srv, WithStorageBatchSize(2))
+
+		acks, err := client.SendContainerProfiles(context.Background(), testProfiles("a", "b", "c"))
+		require.NoError(t, err)
+		require.Len(t, acks, 3)
+		for i, ack := range acks {
+			assert.Equal(t, int64(i), ack.GetIndex())
+			assert.True(t, ack.GetSuccess())
+		}
+		assert.Equal(t, []string{"a", "b", "c"}, srv.names)
+		assert.True(t, client.streamingUnsupported.Load())
+	})
+
+	t.Run("should report the profiles which could not be sent", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewStorageClient("grpc://localhost:50051", "account", "key", "cluster")
+		require.NoError(t, err)
+		client.protoClient = &mockStorageServiceClient{
+			streamContainerProfilesFunc: func(context.Context, ...grpc.CallOption) (grpc.ClientStreamingClient[proto.StreamContainerProfilesRequest, proto.StreamContainerProfilesResponse], error) {
+				return nil, status.Error(codes.Unavailable, "unreachable")
+			},
+		}
+
+		acks, err := client.SendContainerProfiles(context.Background(), testProfiles("a", "b"))
+		require.Error(t, err)
+		assert.Equal(t, codes.Unavailable, status.Code(errors.Unwrap(err)))
+		require.Len(t, acks, 2)
+		for i, ack := range acks {
+			assert.Equal(t, int64(i), ack.GetIndex())
+			assert.False(t, ack.GetSuccess())
+			assert.Contains(t, ack.GetErrorMessage(), "unreachable")
+		}
+	})
+
+	t.Run("should fail when not connected", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewStorageClient("grpc://localhost:50051", "account", "key", "cluster")
+		require.NoError(t, err)
+
+		_, err = client.SendContainerProfiles(context.Background(), testProfiles("a"))
+		require.Error(t, err)
+	})
+}
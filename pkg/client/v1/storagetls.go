@@ -0,0 +1,281 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// storageTLSOptions holds the TLS configuration of a secure (grpcs://) storage connection
+type storageTLSOptions struct {
+	caPEM      []byte
+	caFile     string
+	certPEM    []byte
+	keyPEM     []byte
+	certFile   string
+	keyFile    string
+	serverName string
+	minVersion uint16
+}
+
+// WithStorageCACert trusts the CA certificates in a PEM bundle to verify the storage server, instead of the system CAs
+func WithStorageCACert(pem []byte) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.tls.caPEM = pem
+	}
+}
+
+// WithStorageCACertFile trusts the CA certificates in a PEM file to verify the storage server, instead of the system CAs
+// The file is read again whenever it changes.
+func WithStorageCACertFile(path string) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.tls.caFile = path
+	}
+}
+
+// WithStorageClientCert sets the client certificate and key (PEM encoded) presented to the storage server for mutual TLS
+func WithStorageClientCert(certPEM, keyPEM []byte) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.tls.certPEM = certPEM
+		o.tls.keyPEM = keyPEM
+	}
+}
+
+// WithStorageClientCertFile sets the files holding the client certificate and key (PEM encoded) presented to the storage server for mutual TLS
+// The files are read again whenever they change, so that renewed certificates are picked up by new connections.
+func WithStorageClientCertFile(certFile, keyFile string) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.tls.certFile = certFile
+		o.tls.keyFile = keyFile
+	}
+}
+
+// WithStorageServerName overrides the server name sent with SNI and expected in the server certificate
+// The default is the host of the gRPC URL.
+func WithStorageServerName(serverName string) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.tls.serverName = serverName
+	}
+}
+
+// WithStorageMinTLSVersion sets the minimum TLS version accepted, e.g. tls.VersionTLS13
+// The default is the minimum version of the crypto/tls package (TLS 1.2).
+func WithStorageMinTLSVersion(version uint16) StorageClientOption {
+	return func(o *StorageClientOptions) {
+		o.tls.minVersion = version
+	}
+}
+
+// config builds the TLS configuration of a connection to host
+//
+// Files are read once to report errors early, then again on every handshake if they have changed.
+func (o *storageTLSOptions) config(host string) (*tls.Config, error) {
+	serverName := o.serverName
+	if serverName == "" {
+		serverName = host
+	}
+
+	config := &tls.Config{
+		ServerName: serverName,
+		MinVersion: o.minVersion,
+	}
+
+	switch {
+	case o.caFile != "":
+		roots := newReloadingFiles(func(contents ...[]byte) (*x509.CertPool, error) {
+			return certPool(o.caPEM, contents[0])
+		}, o.caFile)
+		if _, err := roots.get(); err != nil {
+			return nil, err
+		}
+
+		// the roots used by the standard verification are fixed: verify the server with the current roots instead
+		config.InsecureSkipVerify = true //nolint:gosec
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			pool, err := roots.get()
+			if err != nil {
+				return err
+			}
+
+			return verifyServerCertificate(state, serverName, pool)
+		}
+	case len(o.caPEM) > 0:
+		pool, err := certPool(o.caPEM)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	switch {
+	case o.certFile != "" || o.keyFile != "":
+		certificate := newReloadingFiles(func(contents ...[]byte) (*tls.Certificate, error) {
+			cert, err := tls.X509KeyPair(contents[0], contents[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid client certificate: %w", err)
+			}
+
+			return &cert, nil
+		}, o.certFile, o.keyFile)
+		if _, err := certificate.get(); err != nil {
+			return nil, err
+		}
+
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return certificate.get()
+		}
+	case len(o.certPEM) > 0 || len(o.keyPEM) > 0:
+		cert, err := tls.X509KeyPair(o.certPEM, o.keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// certPool builds a pool of the CA certificates held in PEM bundles
+func certPool(bundles ...[]byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, bundle := range bundles {
+		if len(bundle) == 0 {
+			continue
+		}
+
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, errors.New("invalid CA bundle: no PEM encoded certificate found")
+		}
+	}
+
+	return pool, nil
+}
+
+// verifyServerCertificate verifies the certificate chain presented by the server against a pool of CAs
+//
+// The server name is passed explicitly, as no SNI is sent (hence none is reported in the state) when connecting to an IP address.
+func verifyServerCertificate(state tls.ConnectionState, serverName string, roots *x509.CertPool) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no server certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+
+	return err
+}
+
+// reloadingFiles caches a value parsed from files, and parses the files again whenever one of them changes
+//
+// If the files cannot be parsed after a change, e.g. while a certificate is renewed but not its key yet,
+// the last valid value is kept.
+type reloadingFiles[T any] struct {
+	paths []string
+	parse func(contents ...[]byte) (T, error)
+
+	mu     sync.Mutex
+	stamps []fileStamp
+	value  T
+	loaded bool
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func newReloadingFiles[T any](parse func(contents ...[]byte) (T, error), paths ...string) *reloadingFiles[T] {
+	return &reloadingFiles[T]{
+		paths: paths,
+		parse: parse,
+	}
+}
+
+// get yields the value parsed from the current version of the files
+func (r *reloadingFiles[T]) get() (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamps, err := r.stat()
+	if err == nil && r.loaded && sameStamps(stamps, r.stamps) {
+		return r.value, nil
+	}
+
+	if err == nil {
+		var value T
+		if value, err = r.read(); err == nil {
+			r.value = value
+			r.stamps = stamps
+			r.loaded = true
+
+			return value, nil
+		}
+	}
+
+	if r.loaded {
+		return r.value, nil
+	}
+
+	var zero T
+
+	return zero, err
+}
+
+func (r *reloadingFiles[T]) stat() ([]fileStamp, error) {
+	stamps := make([]fileStamp, 0, len(r.paths))
+	for _, path := range r.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		stamps = append(stamps, fileStamp{modTime: info.ModTime(), size: info.Size()})
+	}
+
+	return stamps, nil
+}
+
+func (r *reloadingFiles[T]) read() (T, error) {
+	contents := make([][]byte, 0, len(r.paths))
+	for _, path := range r.paths {
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			var zero T
+
+			return zero, err
+		}
+
+		contents = append(contents, bytes.TrimSpace(buf))
+	}
+
+	return r.parse(contents...)
+}
+
+// sameStamps tells if two sets of files are at the same versions
+func sameStamps(a, b []fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+
+	return true
+}
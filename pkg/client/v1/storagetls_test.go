@@ -0,0 +1,279 @@
+package v1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// testCA is a certificate authority of a test PKI
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t testing.TB, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue yields a certificate and key (PEM encoded) signed by the CA
+func (ca *testCA) issue(t testing.TB, name string, usage x509.ExtKeyUsage, dnsNames ...string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+type tlsStorageServer struct {
+	proto.UnimplementedStorageServiceServer
+}
+
+func (tlsStorageServer) SendContainerProfile(context.Context, *proto.SendContainerProfileRequest) (*proto.SendContainerProfileResponse, error) {
+	return &proto.SendContainerProfileResponse{Success: true}, nil
+}
+
+// startTLSStorageServer starts a storage server requiring client certificates signed by the CA, and yields its address
+func startTLSStorageServer(t testing.TB, ca *testCA) string {
+	certPEM, keyPEM := ca.issue(t, "storage", x509.ExtKeyUsageServerAuth, "storage.internal")
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})))
+	proto.RegisterStorageServiceServer(srv, tlsStorageServer{})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func writeFile(t testing.TB, path string, content []byte) string {
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+
+	return path
+}
+
+func TestStorageClientTLS(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t, "internal-ca")
+	addr := startTLSStorageServer(t, ca)
+	dir := t.TempDir()
+	caFile := writeFile(t, filepath.Join(dir, "ca.crt"), ca.pem)
+	clientCert, clientKey := ca.issue(t, "node-agent", x509.ExtKeyUsageClientAuth)
+	certFile := writeFile(t, filepath.Join(dir, "tls.crt"), clientCert)
+	keyFile := writeFile(t, filepath.Join(dir, "tls.key"), clientKey)
+
+	send := func(t *testing.T, opts ...StorageClientOption) error {
+		client, err := NewStorageClient("grpcs://"+addr, "account", "key", "cluster",
+			append([]StorageClientOption{WithCallTimeout(5 * time.Second)}, opts...)...,
+		)
+		require.NoError(t, err)
+		require.NoError(t, client.Connect())
+		t.Cleanup(func() { _ = client.Close() })
+
+		_, err = client.SendContainerProfile(context.Background(), &v1beta1.ContainerProfile{})
+
+		return err
+	}
+
+	t.Run("should connect with mutual TLS from files", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, send(t,
+			WithStorageCACertFile(caFile),
+			WithStorageClientCertFile(certFile, keyFile),
+			WithStorageServerName("storage.internal"),
+			WithStorageMinTLSVersion(tls.VersionTLS13),
+		))
+	})
+
+	t.Run("should connect with mutual TLS from PEM", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, send(t,
+			WithStorageCACert(ca.pem),
+			WithStorageClientCert(clientCert, clientKey),
+			WithStorageServerName("storage.internal"),
+		))
+	})
+
+	t.Run("should reject an untrusted server", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, send(t,
+			WithStorageCACert(newTestCA(t, "other-ca").pem),
+			WithStorageClientCert(clientCert, clientKey),
+			WithStorageServerName("storage.internal"),
+		))
+	})
+
+	t.Run("should reject a mismatched server name", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, send(t,
+			WithStorageCACertFile(caFile),
+			WithStorageClientCert(clientCert, clientKey),
+		))
+	})
+
+	t.Run("should be rejected without a client certificate", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, send(t,
+			WithStorageCACert(ca.pem),
+			WithStorageServerName("storage.internal"),
+		))
+	})
+
+	t.Run("should fail to connect with invalid TLS settings", func(t *testing.T) {
+		t.Parallel()
+
+		for _, opt := range []StorageClientOption{
+			WithStorageCACert([]byte("not a certificate")),
+			WithStorageCACertFile(filepath.Join(dir, "missing.crt")),
+			WithStorageClientCert(clientCert, ca.pem),
+			WithStorageClientCertFile(certFile, filepath.Join(dir, "missing.key")),
+		} {
+			client, err := NewStorageClient("grpcs://"+addr, "account", "key", "cluster", opt)
+			require.NoError(t, err)
+			require.ErrorContains(t, client.Connect(), "failed to configure TLS")
+		}
+	})
+}
+
+func TestStorageTLSReload(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t, "internal-ca")
+	dir := t.TempDir()
+	cert1, key1 := ca.issue(t, "node-agent-1", x509.ExtKeyUsageClientAuth)
+	certFile := writeFile(t, filepath.Join(dir, "tls.crt"), cert1)
+	keyFile := writeFile(t, filepath.Join(dir, "tls.key"), key1)
+
+	options := storageClientOptionsWithDefaults([]StorageClientOption{
+		WithStorageClientCertFile(certFile, keyFile),
+	})
+	config, err := options.tls.config("storage.internal")
+	require.NoError(t, err)
+
+	commonName := func(t *testing.T) string {
+		cert, err := config.GetClientCertificate(&tls.CertificateRequestInfo{})
+		require.NoError(t, err)
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+
+		return leaf.Subject.CommonName
+	}
+
+	require.Equal(t, "node-agent-1", commonName(t))
+
+	t.Run("should keep the last valid certificate during a renewal", func(t *testing.T) {
+		cert2, key2 := ca.issue(t, "node-agent-2", x509.ExtKeyUsageClientAuth)
+		writeFile(t, certFile, cert2)
+		require.Equal(t, "node-agent-1", commonName(t))
+
+		writeFile(t, keyFile, key2)
+		require.Equal(t, "node-agent-2", commonName(t))
+	})
+}
+
+func TestStorageTLSReloadCA(t *testing.T) {
+	t.Parallel()
+
+	oldCA := newTestCA(t, "old-ca")
+	newCA := newTestCA(t, "new-ca")
+	caFile := writeFile(t, filepath.Join(t.TempDir(), "ca.crt"), oldCA.pem)
+
+	options := storageClientOptionsWithDefaults([]StorageClientOption{
+		WithStorageCACertFile(caFile),
+	})
+	config, err := options.tls.config("storage.internal")
+	require.NoError(t, err)
+
+	serverState := func(t *testing.T, ca *testCA) tls.ConnectionState {
+		certPEM, _ := ca.issue(t, "storage", x509.ExtKeyUsageServerAuth, "storage.internal")
+		block, _ := pem.Decode(certPEM)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+
+		return tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	}
+
+	require.NoError(t, config.VerifyConnection(serverState(t, oldCA)))
+	require.Error(t, config.VerifyConnection(serverState(t, newCA)))
+
+	t.Run("should trust a rotated CA", func(t *testing.T) {
+		writeFile(t, caFile, append(append([]byte{}, oldCA.pem...), newCA.pem...))
+
+		require.NoError(t, config.VerifyConnection(serverState(t, oldCA)))
+		require.NoError(t, config.VerifyConnection(serverState(t, newCA)))
+	})
+}
@@ -0,0 +1,261 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/armosec/armoapi-go/armotypes"
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Delays between attempts to resume an interrupted watch
+const (
+	watchRetryInitialDelay = 100 * time.Millisecond
+	watchRetryMaxDelay     = 10 * time.Second
+)
+
+// errWatchEnded is returned once a watch delivered its final error event
+var errWatchEnded = errors.New("watch ended")
+
+// Watch watches the changes of ApplicationProfiles or NetworkNeighborhoods in a namespace, following the Kubernetes watch model
+// kind is armotypes.ApplicationProfileKind or armotypes.NetworkNeighborhoodKind, and events carry a *v1beta1.ApplicationProfile or a *v1beta1.NetworkNeighborhood.
+// Bookmark events, requested with WithWatchBookmarks, only carry the resource version in the metadata of the object.
+//
+// When the stream is interrupted, the watch resumes after the last resource version received.
+// The watch ends with an error event holding a *metav1.Status when the server rejects it: a resource version
+// which is too old is reported as 410 Gone (see apierrors.IsResourceExpired), after which the profiles should be listed again.
+//
+// The call timeout does not apply: the watch runs until Stop is called or the context is done.
+func (c *StorageClient) Watch(ctx context.Context, kind armotypes.ProfileKind, namespace string, opts ...WatchOption) (watch.Interface, error) {
+	if c.protoClient == nil {
+		return nil, fmt.Errorf("client is not connected")
+	}
+
+	if kind != armotypes.ApplicationProfileKind && kind != armotypes.NetworkNeighborhoodKind {
+		return nil, fmt.Errorf("cannot watch kind %q: expected ApplicationProfile or NetworkNeighborhood", kind)
+	}
+
+	watchOpts := watchOptionsWithDefaults(opts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &profileWatcher{
+		client:      c,
+		protoClient: c.protoClient,
+		cancel:      cancel,
+		result:      make(chan watch.Event),
+		req: &proto.WatchProfilesRequest{
+			Kind:                   string(kind),
+			Namespace:              namespace,
+			ResourceVersion:        watchOpts.ResourceVersion,
+			AllowBookmarks:         watchOpts.AllowBookmarks,
+			Region:                 watchOpts.Region,
+			CloudAccountIdentifier: watchOpts.CloudAccountIdentifier,
+		},
+	}
+
+	go w.run(ctx)
+
+	return w, nil
+}
+
+// profileWatcher implements watch.Interface on top of the WatchProfiles stream
+//
+// The proto client is captured when the watch starts: once the client is closed, resuming fails
+// and the watch ends with an error event.
+type profileWatcher struct {
+	client      *StorageClient
+	protoClient proto.StorageServiceClient
+	cancel      context.CancelFunc
+	result      chan watch.Event
+	req         *proto.WatchProfilesRequest // the resource version is advanced as events are received
+}
+
+// Stop stops the watch, and closes the result channel
+func (w *profileWatcher) Stop() {
+	w.cancel()
+}
+
+// ResultChan returns the channel receiving the events
+func (w *profileWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// run streams events until the watch is stopped or rejected, resuming interrupted streams
+func (w *profileWatcher) run(ctx context.Context) {
+	defer close(w.result)
+	defer w.cancel()
+
+	delay := watchRetryInitialDelay
+	for {
+		received, err := w.receive(ctx)
+		if ctx.Err() != nil || errors.Is(err, errWatchEnded) {
+			return
+		}
+
+		if err != nil && !isWatchRetryable(err) {
+			w.send(ctx, watchErrorEvent(watchErrorCode(err), status.Convert(err).Message()))
+			return
+		}
+
+		if received {
+			// the stream was healthy: resume at once
+			delay = watchRetryInitialDelay
+			continue
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		delay = min(2*delay, watchRetryMaxDelay)
+	}
+}
+
+// receive delivers the events of one WatchProfiles stream, and reports whether any event was received
+// It returns errWatchEnded once an error event was delivered.
+func (w *profileWatcher) receive(ctx context.Context) (bool, error) {
+	// the metadata is taken for each stream, to follow credential updates
+	stream, err := w.protoClient.WatchProfiles(w.client.withMetadata(ctx), w.req)
+	if err != nil {
+		return false, err
+	}
+
+	received := false
+	for {
+		ev, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+		received = true
+
+		event, ok := w.event(ev)
+		if !ok {
+			continue
+		}
+
+		if !w.send(ctx, event) {
+			return received, ctx.Err()
+		}
+
+		if event.Type == watch.Error {
+			return received, errWatchEnded
+		}
+	}
+}
+
+// send delivers an event, unless the watch is stopped
+func (w *profileWatcher) send(ctx context.Context, event watch.Event) bool {
+	select {
+	case w.result <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// event converts a received event, and records its resource version
+func (w *profileWatcher) event(ev *proto.WatchProfilesEvent) (watch.Event, bool) {
+	var eventType watch.EventType
+	switch ev.GetType() {
+	case proto.WatchEventType_WATCH_EVENT_TYPE_ADDED:
+		eventType = watch.Added
+	case proto.WatchEventType_WATCH_EVENT_TYPE_MODIFIED:
+		eventType = watch.Modified
+	case proto.WatchEventType_WATCH_EVENT_TYPE_DELETED:
+		eventType = watch.Deleted
+	case proto.WatchEventType_WATCH_EVENT_TYPE_BOOKMARK:
+		eventType = watch.Bookmark
+	case proto.WatchEventType_WATCH_EVENT_TYPE_ERROR:
+		return watchErrorEvent(ev.GetErrorCode(), ev.GetErrorMessage()), true
+	default:
+		return watch.Event{}, false
+	}
+
+	var obj runtime.Object
+	var objMeta *metav1.ObjectMeta
+	if armotypes.ProfileKind(w.req.Kind) == armotypes.ApplicationProfileKind {
+		profile := ev.GetApplicationProfile()
+		if profile == nil {
+			profile = &v1beta1.ApplicationProfile{}
+		}
+		obj, objMeta = profile, &profile.ObjectMeta
+	} else {
+		neighborhood := ev.GetNetworkNeighborhood()
+		if neighborhood == nil {
+			neighborhood = &v1beta1.NetworkNeighborhood{}
+		}
+		obj, objMeta = neighborhood, &neighborhood.ObjectMeta
+	}
+
+	resourceVersion := ev.GetResourceVersion()
+	if resourceVersion == "" {
+		resourceVersion = objMeta.ResourceVersion
+	}
+	if objMeta.ResourceVersion == "" {
+		objMeta.ResourceVersion = resourceVersion
+	}
+	if resourceVersion != "" {
+		w.req.ResourceVersion = resourceVersion
+	}
+
+	return watch.Event{Type: eventType, Object: obj}, true
+}
+
+// isWatchRetryable tells whether an interrupted stream should be resumed
+func isWatchRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.ResourceExhausted, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
+
+// watchErrorCode maps the status of a rejected stream to an error code
+func watchErrorCode(err error) proto.ErrorCode {
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return proto.ErrorCode_ERROR_CODE_UNAUTHORIZED
+	case codes.InvalidArgument:
+		return proto.ErrorCode_ERROR_CODE_INVALID_REQUEST
+	case codes.OutOfRange:
+		return proto.ErrorCode_ERROR_CODE_RESOURCE_EXPIRED
+	default:
+		return proto.ErrorCode_ERROR_CODE_INTERNAL_ERROR
+	}
+}
+
+// watchErrorEvent builds an error event holding the Kubernetes status matching the error code
+func watchErrorEvent(code proto.ErrorCode, message string) watch.Event {
+	var err *apierrors.StatusError
+	switch code {
+	case proto.ErrorCode_ERROR_CODE_RESOURCE_EXPIRED:
+		err = apierrors.NewResourceExpired(message)
+	case proto.ErrorCode_ERROR_CODE_UNAUTHORIZED:
+		err = apierrors.NewUnauthorized(message)
+	case proto.ErrorCode_ERROR_CODE_INVALID_REQUEST:
+		err = apierrors.NewBadRequest(message)
+	default:
+		err = apierrors.NewInternalError(errors.New(message))
+	}
+
+	status := err.Status()
+
+	return watch.Event{Type: watch.Error, Object: &status}
+}
@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/armosec/armoapi-go/armotypes"
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"github.com/kubescape/storage/pkg/apis/softwarecomposition/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// watchStorageServer serves each WatchProfiles call with the next scripted stream, then blocks
+type watchStorageServer struct {
+	proto.UnimplementedStorageServiceServer
+
+	mu       sync.Mutex
+	streams  []func(grpc.ServerStreamingServer[proto.WatchProfilesEvent]) error
+	requests []*proto.WatchProfilesRequest
+}
+
+func (s *watchStorageServer) WatchProfiles(req *proto.WatchProfilesRequest, stream grpc.ServerStreamingServer[proto.WatchProfilesEvent]) error {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	var serve func(grpc.ServerStreamingServer[proto.WatchProfilesEvent]) error
+	if len(s.streams) > 0 {
+		serve, s.streams = s.streams[0], s.streams[1:]
+	}
+	s.mu.Unlock()
+
+	if serve == nil {
+		<-stream.Context().Done()
+		return nil
+	}
+
+	return serve(stream)
+}
+
+func (s *watchStorageServer) resourceVersions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := make([]string, 0, len(s.requests))
+	for _, req := range s.requests {
+		versions = append(versions, req.GetResourceVersion())
+	}
+
+	return versions
+}
+
+// sendEvents returns a scripted stream sending the events, then ending with err
+func sendEvents(err error, events ...*proto.WatchProfilesEvent) func(grpc.ServerStreamingServer[proto.WatchProfilesEvent]) error {
+	return func(stream grpc.ServerStreamingServer[proto.WatchProfilesEvent]) error {
+		for _, ev := range events {
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		}
+
+		return err
+	}
+}
+
+func profileEvent(eventType proto.WatchEventType, name, resourceVersion string) *proto.WatchProfilesEvent {
+	return &proto.WatchProfilesEvent{
+		Type:               eventType,
+		ResourceVersion:    resourceVersion,
+		ApplicationProfile: &v1beta1.ApplicationProfile{ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: resourceVersion}},
+	}
+}
+
+func nextEvent(t *testing.T, w watch.Interface) watch.Event {
+	t.Helper()
+
+	select {
+	case event, ok := <-w.ResultChan():
+		require.True(t, ok, "the watch ended")
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no event received")
+		return watch.Event{}
+	}
+}
+
+func requireWatchEnded(t *testing.T, w watch.Interface) {
+	t.Helper()
+
+	select {
+	case _, ok := <-w.ResultChan():
+		require.False(t, ok, "the watch is still running")
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the watch did not end")
+	}
+}
+
+func TestStorageClient_Watch(t *testing.T) {
+	t.Parallel()
+
+	connect := func(t *testing.T, srv proto.StorageServiceServer) *StorageClient {
+		grpcURL, _ := startStorageServer(t, srv)
+
+		client, err := NewStorageClient(grpcURL, "account", "key", "cluster")
+		require.NoError(t, err)
+		require.NoError(t, client.Connect())
+		t.Cleanup(func() { _ = client.Close() })
+
+		return client
+	}
+
+	t.Run("should deliver events and resume after a disconnection", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &watchStorageServer{streams: []func(grpc.ServerStreamingServer[proto.WatchProfilesEvent]) error{
+			sendEvents(status.Error(codes.Unavailable, "restarting"),
+				profileEvent(proto.WatchEventType_WATCH_EVENT_TYPE_ADDED, "nginx", "11"),
+				profileEvent(proto.WatchEventType_WATCH_EVENT_TYPE_MODIFIED, "nginx", "12"),
+			),
+			sendEvents(nil,
+				&proto.WatchProfilesEvent{Type: proto.WatchEventType_WATCH_EVENT_TYPE_BOOKMARK, ResourceVersion: "13"},
+			),
+			sendEvents(nil,
+				profileEvent(proto.WatchEventType_WATCH_EVENT_TYPE_DELETED, "nginx", "14"),
+			),
+		}}
+		client := connect(t, srv)
+
+		w, err := client.Watch(context.Background(), armotypes.ApplicationProfileKind, "default", WithWatchResourceVersion("10"), WithWatchBookmarks())
+		require.NoError(t, err)
+		defer w.Stop()
+
+		expected := []struct {
+			eventType       watch.EventType
+			name            string
+			resourceVersion string
+		}{
+			{watch.Added, "nginx", "11"},
+			{watch.Modified, "nginx", "12"},
+			{watch.Bookmark, "", "13"},
+			{watch.Deleted, "nginx", "14"},
+		}
+		for _, want := range expected {
+			event := nextEvent(t, w)
+			require.Equal(t, want.eventType, event.Type)
+			profile, ok := event.Object.(*v1beta1.ApplicationProfile)
+			require.True(t, ok)
+			assert.Equal(t, want.name, profile.Name)
+			assert.Equal(t, want.resourceVersion, profile.ResourceVersion)
+		}
+
+		// the last stream ended without error: the watch resumes once more
+		require.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"10", "12", "13", "14"}, srv.resourceVersions())
+		}, 5*time.Second, 10*time.Millisecond)
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		assert.True(t, srv.requests[0].GetAllowBookmarks())
+		assert.Equal(t, "ApplicationProfile", srv.requests[0].GetKind())
+		assert.Equal(t, "default", srv.requests[0].GetNamespace())
+	})
+
+	t.Run("should end with a 410 Gone status when the resource version expired", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &watchStorageServer{streams: []func(grpc.ServerStreamingServer[proto.WatchProfilesEvent]) error{
+			sendEvents(nil, &proto.WatchProfilesEvent{
+				Type:         proto.WatchEventType_WATCH_EVENT_TYPE_ERROR,
+				ErrorMessage: "too old resource version: 1",
+				ErrorCode:    proto.ErrorCode_ERROR_CODE_RESOURCE_EXPIRED,
+			}),
+		}}
+		client := connect(t, srv)
+
+		w, err := client.Watch(context.Background(), armotypes.NetworkNeighborhoodKind, "default", WithWatchResourceVersion("1"))
+		require.NoError(t, err)
+		defer w.Stop()
+
+		event := nextEvent(t, w)
+		require.Equal(t, watch.Error, event.Type)
+		statusErr := apierrors.FromObject(event.Object)
+		assert.True(t, apierrors.IsResourceExpired(statusErr))
+		assert.Contains(t, statusErr.Error(), "too old resource version")
+		requireWatchEnded(t, w)
+	})
+
+	t.Run("should end with an error status when the server rejects the watch", func(t *testing.T) {
+		t.Parallel()
+
+		client := connect(t, &unaryStorageServer{})
+
+		w, err := client.Watch(context.Background(), armotypes.ApplicationProfileKind, "default")
+		require.NoError(t, err)
+		defer w.Stop()
+
+		event := nextEvent(t, w)
+		require.Equal(t, watch.Error, event.Type)
+		assert.Contains(t, apierrors.FromObject(event.Object).Error(), "not implemented")
+		requireWatchEnded(t, w)
+	})
+
+	t.Run("should end with an error status when the client is closed while resuming", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &watchStorageServer{streams: []func(grpc.ServerStreamingServer[proto.WatchProfilesEvent]) error{
+			sendEvents(status.Error(codes.Unavailable, "restarting")),
+		}}
+		client := connect(t, srv)
+
+		w, err := client.Watch(context.Background(), armotypes.ApplicationProfileKind, "default")
+		require.NoError(t, err)
+		defer w.Stop()
+
+		// the interrupted stream is resumed after a delay, during which the client is closed
+		require.Eventually(t, func() bool {
+			return len(srv.resourceVersions()) == 1
+		}, 5*time.Second, time.Millisecond)
+		require.NoError(t, client.Close())
+
+		event := nextEvent(t, w)
+		require.Equal(t, watch.Error, event.Type)
+		requireWatchEnded(t, w)
+	})
+
+	t.Run("should close the result channel when stopped", func(t *testing.T) {
+		t.Parallel()
+
+		client := connect(t, &watchStorageServer{})
+
+		w, err := client.Watch(context.Background(), armotypes.ApplicationProfileKind, "default")
+		require.NoError(t, err)
+
+		w.Stop()
+		requireWatchEnded(t, w)
+	})
+
+	t.Run("should reject other kinds", func(t *testing.T) {
+		t.Parallel()
+
+		client := connect(t, &watchStorageServer{})
+
+		_, err := client.Watch(context.Background(), armotypes.ContainerProfileKind, "default")
+		require.Error(t, err)
+	})
+
+	t.Run("should fail when not connected", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewStorageClient("grpc://localhost:50051", "account", "key", "cluster")
+		require.NoError(t, err)
+
+		_, err = client.Watch(context.Background(), armotypes.ApplicationProfileKind, "default")
+		require.Error(t, err)
+	})
+}
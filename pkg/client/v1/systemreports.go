@@ -3,6 +3,7 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"sync"
 	"time"
@@ -52,6 +53,7 @@ type BaseReportSender struct {
 	report           *systemreports.BaseReport
 	headers          map[string]string
 	httpSender       IHttpSender
+	circuitBreaker   *CircuitBreaker
 }
 
 type sysEndpoint struct {
@@ -106,6 +108,14 @@ func (e *sysEndpoint) GetOrDefault() string {
 	return e.Get()
 }
 
+// SetCircuitBreaker protects the event receiver with a circuit breaker.
+//
+// The same circuit breaker should be shared by all senders reporting to the same event receiver.
+// When the circuit is open, Send fails fast with ErrCircuitOpen.
+func (s *BaseReportSender) SetCircuitBreaker(cb *CircuitBreaker) {
+	s.circuitBreaker = cb
+}
+
 // Send - send http request. returns-> http status code, return message (jobID/OK), http/go error
 func (s *BaseReportSender) Send() (int, string, error) {
 	scheme, host, err := utils.ParseHost(s.eventReceiverUrl)
@@ -129,7 +139,7 @@ func (s *BaseReportSender) Send() (int, string, error) {
 		return 500, "Couldn't marshall report object", err
 	}
 
-	statusCode, bodyAsStr, err := s.httpSender.Send(url.String(), s.headers, reqBody)
+	statusCode, bodyAsStr, err := s.sendWithCircuitBreaker(url.String(), reqBody)
 	if err != nil {
 		return statusCode, bodyAsStr, err
 	}
@@ -142,6 +152,22 @@ func (s *BaseReportSender) Send() (int, string, error) {
 
 }
 
+func (s *BaseReportSender) sendWithCircuitBreaker(serverURL string, reqBody []byte) (int, string, error) {
+	if s.circuitBreaker == nil {
+		return s.httpSender.Send(serverURL, s.headers, reqBody)
+	}
+
+	generation, err := s.circuitBreaker.allow()
+	if err != nil {
+		return http.StatusServiceUnavailable, "", err
+	}
+
+	statusCode, bodyAsStr, err := s.httpSender.Send(serverURL, s.headers, reqBody)
+	s.circuitBreaker.record(generation, &http.Response{StatusCode: statusCode}, err)
+
+	return statusCode, bodyAsStr, err
+}
+
 // The caller must read the errChan, to prevent the goroutine from waiting in memory forever
 func (sender *BaseReportSender) SendAsRoutine(progressNext bool) {
 	sender.report.Mutex.Lock()
@@ -0,0 +1,362 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// instrumentationName identifies the spans and metrics emitted by the clients in this package.
+const instrumentationName = "github.com/kubescape/backend/pkg/client/v1"
+
+// Attribute keys set on spans and metrics.
+const (
+	attrHTTPMethod     = attribute.Key("http.request.method")
+	attrHTTPRoute      = attribute.Key("http.route")
+	attrHTTPStatusCode = attribute.Key("http.response.status_code")
+	attrServerAddress  = attribute.Key("server.address")
+	attrRPCMethod      = attribute.Key("rpc.method")
+	attrRPCStatusCode  = attribute.Key("rpc.grpc.status_code")
+	attrErrorCode      = attribute.Key("kubescape.storage.error_code")
+)
+
+// tracePropagator propagates the W3C trace context and baggage to the backend.
+var tracePropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// telemetry holds the OpenTelemetry instruments of a client.
+type telemetry struct {
+	tracer        trace.Tracer
+	requests      metric.Int64Counter
+	errors        metric.Int64Counter
+	duration      metric.Float64Histogram
+	bytesSent     metric.Int64Counter
+	bytesReceived metric.Int64Counter
+}
+
+// newTelemetry builds the instruments of a client, e.g. "kscloud.client" or "storage.client".
+//
+// Nil providers fall back to the global providers, which do nothing unless configured by the application.
+func newTelemetry(prefix string, tp trace.TracerProvider, mp metric.MeterProvider) *telemetry {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	meter := mp.Meter(instrumentationName)
+	t := &telemetry{
+		tracer: tp.Tracer(instrumentationName),
+	}
+
+	// instrument creation only fails on invalid names: a no-op instrument is returned in that case
+	t.requests, _ = meter.Int64Counter(prefix+".requests",
+		metric.WithDescription("Number of requests sent."),
+		metric.WithUnit("{request}"),
+	)
+	t.errors, _ = meter.Int64Counter(prefix+".errors",
+		metric.WithDescription("Number of failed requests."),
+		metric.WithUnit("{request}"),
+	)
+	t.duration, _ = meter.Float64Histogram(prefix+".duration",
+		metric.WithDescription("Duration of requests."),
+		metric.WithUnit("s"),
+	)
+	t.bytesSent, _ = meter.Int64Counter(prefix+".sent",
+		metric.WithDescription("Size of request payloads."),
+		metric.WithUnit("By"),
+	)
+	t.bytesReceived, _ = meter.Int64Counter(prefix+".received",
+		metric.WithDescription("Size of response payloads."),
+		metric.WithUnit("By"),
+	)
+
+	return t
+}
+
+// roundTrip sends an HTTP request within a client span, and records metrics.
+//
+// The trace context is propagated with W3C headers. Received bytes are recorded when the response body is closed.
+func (t *telemetry) roundTrip(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	attrs := []attribute.KeyValue{
+		attrHTTPMethod.String(req.Method),
+		attrHTTPRoute.String(req.URL.Path),
+		attrServerAddress.String(req.URL.Host),
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), req.Method+" "+req.URL.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	tracePropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	var sent *countingReader
+	if req.Body != nil && req.Body != http.NoBody {
+		sent = &countingReader{ReadCloser: req.Body}
+		req.Body = sent
+	}
+
+	start := time.Now()
+	resp, err := send(req)
+
+	if err == nil {
+		attrs = append(attrs, attrHTTPStatusCode.Int(resp.StatusCode))
+		span.SetAttributes(attrHTTPStatusCode.Int(resp.StatusCode))
+	}
+	set := metric.WithAttributes(attrs...)
+
+	t.requests.Add(ctx, 1, set)
+	t.duration.Record(ctx, time.Since(start).Seconds(), set)
+	if sent != nil {
+		t.bytesSent.Add(ctx, sent.n.Load(), set)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		t.errors.Add(ctx, 1, set)
+
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+		t.errors.Add(ctx, 1, set)
+	}
+
+	resp.Body = &countingReader{
+		ReadCloser: resp.Body,
+		onClose: func(n int64) {
+			t.bytesReceived.Add(ctx, n, set)
+		},
+	}
+
+	return resp, nil
+}
+
+// unaryInterceptor wraps unary gRPC calls within a client span, and records metrics.
+//
+// The trace context is propagated with W3C keys in the outgoing metadata. Responses reporting
+// an unsuccessful outcome are counted as errors, by ErrorCode.
+func (t *telemetry) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span, attrs := t.startRPC(ctx, method, cc)
+	defer span.End()
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	t.endRPC(ctx, span, attrs, time.Since(start), messageSize(req), reply, err)
+
+	return err
+}
+
+// streamInterceptor wraps streaming gRPC calls within a client span, which ends with the stream.
+func (t *telemetry) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, span, attrs := t.startRPC(ctx, method, cc)
+
+	start := time.Now()
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		t.endRPC(ctx, span, attrs, time.Since(start), 0, nil, err)
+		span.End()
+
+		return nil, err
+	}
+
+	return &instrumentedStream{
+		ClientStream:  stream,
+		ctx:           ctx,
+		telemetry:     t,
+		span:          span,
+		attrs:         attrs,
+		start:         start,
+		serverStreams: desc.ServerStreams,
+	}, nil
+}
+
+func (t *telemetry) startRPC(ctx context.Context, method string, cc *grpc.ClientConn) (context.Context, trace.Span, []attribute.KeyValue) {
+	attrs := []attribute.KeyValue{
+		attrRPCMethod.String(strings.TrimPrefix(method, "/")),
+	}
+	if cc != nil {
+		attrs = append(attrs, attrServerAddress.String(cc.Target()))
+	}
+
+	ctx, span := t.tracer.Start(ctx, strings.TrimPrefix(method, "/"),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	tracePropagator.Inject(ctx, metadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md), span, attrs
+}
+
+// endRPC records the outcome of a gRPC call: its status code, and the ErrorCode of an unsuccessful response.
+func (t *telemetry) endRPC(ctx context.Context, span trace.Span, attrs []attribute.KeyValue, elapsed time.Duration, sent int, reply any, err error) {
+	attrs = append(attrs, attrRPCStatusCode.Int(int(status.Code(err))))
+
+	errorCode, failed := responseErrorCode(reply)
+	if err == nil && failed {
+		attrs = append(attrs, attrErrorCode.String(errorCode.String()))
+	}
+
+	span.SetAttributes(attrs...)
+	set := metric.WithAttributes(attrs...)
+
+	t.requests.Add(ctx, 1, set)
+	t.duration.Record(ctx, elapsed.Seconds(), set)
+	t.bytesSent.Add(ctx, int64(sent), set)
+	t.bytesReceived.Add(ctx, int64(messageSize(reply)), set)
+
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		t.errors.Add(ctx, 1, set)
+	case failed:
+		span.SetStatus(codes.Error, errorCode.String())
+		t.errors.Add(ctx, 1, set)
+	}
+}
+
+// instrumentedStream accounts for the messages of a streaming call, and ends its span with the stream.
+type instrumentedStream struct {
+	grpc.ClientStream
+
+	ctx       context.Context
+	telemetry *telemetry
+	span      trace.Span
+	attrs     []attribute.KeyValue
+	start     time.Time
+
+	serverStreams bool
+	sent          atomic.Int64
+	last          any
+	once          sync.Once
+}
+
+func (s *instrumentedStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(int64(messageSize(m)))
+	} else if err != io.EOF {
+		s.end(err)
+	}
+
+	return err
+}
+
+// RecvMsg ends the call when the single response of a client stream is received, or when a server stream ends.
+func (s *instrumentedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil && !s.serverStreams:
+		s.last = m
+		s.end(nil)
+	case err == io.EOF:
+		s.end(nil)
+	case err != nil:
+		s.end(err)
+	}
+
+	return err
+}
+
+func (s *instrumentedStream) end(err error) {
+	s.once.Do(func() {
+		s.telemetry.endRPC(s.ctx, s.span, s.attrs, time.Since(s.start), int(s.sent.Load()), s.last, err)
+		s.span.End()
+	})
+}
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// responseErrorCode extracts the ErrorCode of an unsuccessful storage response.
+func responseErrorCode(reply any) (proto.ErrorCode, bool) {
+	resp, ok := reply.(interface {
+		GetSuccess() bool
+		GetErrorCode() proto.ErrorCode
+	})
+	if !ok || resp.GetSuccess() {
+		return proto.ErrorCode_ERROR_CODE_UNSPECIFIED, false
+	}
+
+	return resp.GetErrorCode(), true
+}
+
+func messageSize(m any) int {
+	msg, ok := m.(gogoproto.Message)
+	if !ok {
+		return 0
+	}
+
+	return gogoproto.Size(msg)
+}
+
+// countingReader counts the bytes read from a body, and reports the count when closed.
+type countingReader struct {
+	io.ReadCloser
+	n       atomic.Int64
+	onClose func(int64)
+	once    sync.Once
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	if r.onClose != nil {
+		r.once.Do(func() { r.onClose(r.n.Load()) })
+	}
+
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kubescape/backend/pkg/client/v1/proto"
+	backendServer "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// telemetryRecorder collects the spans and metrics emitted during a test.
+type telemetryRecorder struct {
+	spans  *tracetest.SpanRecorder
+	tp     *sdktrace.TracerProvider
+	reader *sdkmetric.ManualReader
+	mp     *sdkmetric.MeterProvider
+}
+
+func newTelemetryRecorder() *telemetryRecorder {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	return &telemetryRecorder{
+		spans:  spans,
+		tp:     sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		reader: reader,
+		mp:     sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}
+}
+
+// sum yields the total of a counter, over all data points matching an attribute.
+func (r *telemetryRecorder) sum(t testing.TB, name string, match attribute.KeyValue) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, r.reader.Collect(context.Background(), &rm))
+
+	var total int64
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != name {
+				continue
+			}
+
+			data, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			for _, point := range data.DataPoints {
+				if value, found := point.Attributes.Value(match.Key); found && value == match.Value {
+					total += point.Value
+				}
+			}
+		}
+	}
+
+	return total
+}
+
+func TestKSCloudAPITelemetry(t *testing.T) {
+	t.Parallel()
+
+	recorder := newTelemetryRecorder()
+
+	var traceParent atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent.Store(r.Header.Get("Traceparent"))
+		if r.Method == http.MethodGet {
+			http.Error(w, "not found", http.StatusNotFound)
+
+			return
+		}
+
+		echoBody(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	ks, err := NewKSCloudAPI(srv.URL, srv.URL, "account", "",
+		WithTracerProvider(recorder.tp),
+		WithMeterProvider(recorder.mp),
+	)
+	require.NoError(t, err)
+
+	ctx, parent := recorder.tp.Tracer("test").Start(context.Background(), "parent")
+	_, err = ks.SubmitReportCtx(ctx, mockPostureReport(t, "", ""))
+	require.NoError(t, err)
+	parent.End()
+
+	_, err = ks.GetAttackTracks()
+	require.ErrorIs(t, err, ErrNotFound)
+
+	t.Run("should trace requests and propagate the trace context", func(t *testing.T) {
+		spans := recorder.spans.Ended()
+		require.Len(t, spans, 3)
+
+		post := spans[0]
+		require.Equal(t, http.MethodPost+" "+backendServer.ReporterReportPath, post.Name())
+		require.Equal(t, parent.SpanContext().TraceID(), post.SpanContext().TraceID())
+		require.Equal(t, parent.SpanContext().SpanID(), post.Parent().SpanID())
+		require.Equal(t, codes.Unset, post.Status().Code)
+
+		get := spans[2]
+		require.Equal(t, codes.Error, get.Status().Code)
+		require.Contains(t, traceParent.Load(), get.SpanContext().TraceID().String())
+	})
+
+	t.Run("should record metrics", func(t *testing.T) {
+		post := attrHTTPMethod.String(http.MethodPost)
+		get := attrHTTPMethod.String(http.MethodGet)
+
+		require.Equal(t, int64(1), recorder.sum(t, "kscloud.client.requests", post))
+		require.Equal(t, int64(1), recorder.sum(t, "kscloud.client.requests", get))
+		require.Zero(t, recorder.sum(t, "kscloud.client.errors", post))
+		require.Equal(t, int64(1), recorder.sum(t, "kscloud.client.errors", attrHTTPStatusCode.Int(http.StatusNotFound)))
+		require.Positive(t, recorder.sum(t, "kscloud.client.sent", post))
+		require.Positive(t, recorder.sum(t, "kscloud.client.received", post))
+	})
+}
+
+func TestStorageClientTelemetry(t *testing.T) {
+	t.Parallel()
+
+	recorder := newTelemetryRecorder()
+	client, err := NewStorageClient("grpc://localhost:50051", "account", "access-key", "cluster",
+		WithStorageTracerProvider(recorder.tp),
+		WithStorageMeterProvider(recorder.mp),
+	)
+	require.NoError(t, err)
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, _ string, _, reply any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		resp := reply.(*proto.GetProfileResponse)
+		resp.Success = false
+		resp.ErrorCode = proto.ErrorCode_ERROR_CODE_PROFILE_NOT_FOUND
+
+		return nil
+	}
+
+	ctx := client.withMetadata(context.Background())
+	err = client.telemetry.unaryInterceptor(ctx, proto.StorageService_GetProfile_FullMethodName,
+		&proto.GetProfileRequest{Kind: "ApplicationProfile", Namespace: "default", Name: "nginx"},
+		&proto.GetProfileResponse{}, nil, invoker,
+	)
+	require.NoError(t, err)
+
+	spans := recorder.spans.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "storageserver.v1.StorageService/GetProfile", spans[0].Name())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+
+	// the trace context is added to the authentication metadata
+	require.Equal(t, []string{"access-key"}, outgoing.Get(backendServer.GrpcAccessKeyHeader))
+	require.Len(t, outgoing.Get("traceparent"), 1)
+	require.Contains(t, outgoing.Get("traceparent")[0], spans[0].SpanContext().TraceID().String())
+
+	errorCode := attrErrorCode.String(proto.ErrorCode_ERROR_CODE_PROFILE_NOT_FOUND.String())
+	require.Equal(t, int64(1), recorder.sum(t, "storage.client.requests", errorCode))
+	require.Equal(t, int64(1), recorder.sum(t, "storage.client.errors", errorCode))
+	require.Positive(t, recorder.sum(t, "storage.client.sent", attrRPCMethod.String("storageserver.v1.StorageService/GetProfile")))
+}
@@ -6,19 +6,19 @@ import (
 )
 
 // buildAPIURL builds an URL pointing to the API backend.
-func (api *KSCloudAPI) buildAPIURL(pth string, pairs ...string) string {
+func (settings ksCloudSettings) buildAPIURL(pth string, pairs ...string) string {
 	return buildQuery(url.URL{
-		Scheme: api.apiScheme,
-		Host:   api.apiHost,
+		Scheme: settings.apiScheme,
+		Host:   settings.apiHost,
 		Path:   pth,
 	}, pairs...)
 }
 
 // buildReportURL builds an URL pointing to the reporting endpoint.
-func (api *KSCloudAPI) buildReportURL(pth string, pairs ...string) string {
+func (settings ksCloudSettings) buildReportURL(pth string, pairs ...string) string {
 	return buildQuery(url.URL{
-		Scheme: api.reportScheme,
-		Host:   api.reportHost,
+		Scheme: settings.reportScheme,
+		Host:   settings.reportHost,
 		Path:   pth,
 	}, pairs...)
 }
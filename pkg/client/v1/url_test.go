@@ -20,7 +20,7 @@ func TestBuildURL(t *testing.T) {
 	t.Run("should build API URL with query params on https host", func(t *testing.T) {
 		require.Equal(t,
 			"https://api.example.com/path?q1=v1&q2=v2",
-			ks.buildAPIURL("/path", "q1", "v1", "q2", "v2"),
+			ks.current().buildAPIURL("/path", "q1", "v1", "q2", "v2"),
 		)
 	})
 
@@ -30,21 +30,21 @@ func TestBuildURL(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t,
 			"http://api.example.com/path?q1=v1&q2=v2",
-			ku.buildAPIURL("/path", "q1", "v1", "q2", "v2"),
+			ku.current().buildAPIURL("/path", "q1", "v1", "q2", "v2"),
 		)
 	})
 
 	t.Run("should panic when params are not provided in pairs", func(t *testing.T) {
 		require.Panics(t, func() {
 			// notice how the linter detects wrong args
-			_ = ks.buildAPIURL("/path", "q1", "v1", "q2") //nolint:staticcheck
+			_ = ks.current().buildAPIURL("/path", "q1", "v1", "q2") //nolint:staticcheck
 		})
 	})
 
 	t.Run("should build report URL with query params on https host", func(t *testing.T) {
 		require.Equal(t,
 			"https://report.example.com/path?q1=v1&q2=v2",
-			ks.buildReportURL("/path", "q1", "v1", "q2", "v2"),
+			ks.current().buildReportURL("/path", "q1", "v1", "q2", "v2"),
 		)
 	})
 }
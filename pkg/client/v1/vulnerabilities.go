@@ -49,8 +49,9 @@ func fetchCVEExceptions(url *url.URL, headers map[string]string) ([]armotypes.Vu
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("fetchCVEExceptions: resp.StatusCode %d", resp.StatusCode)
+		return nil, fmt.Errorf("fetchCVEExceptions: %w", utils.ErrAPI(resp))
 	}
+	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -6,6 +6,7 @@ const (
 	ApiServerFrameworksPath                   = "/api/v1/frameworks"
 	ApiServerExceptionsPath                   = "/api/v1/controlExceptions" // TODO: rename to controlExceptions
 	ApiServerCustomerConfigPath               = "/api/v1/customerConfig"
+	ApiServerControlsPath                     = "/api/v1/controls"
 	ApiServerVulnerabilitiesExceptionsPathOld = "/api/v1/armoVulnerabilityExceptions"
 	ApiServerVulnerabilitiesExceptionsPath    = "/api/v1/vulnerabilityExceptions"
 
@@ -35,6 +36,7 @@ const (
 	RegolibraryVersion            = "v2"
 
 	AccessKeyHeader = "X-API-KEY"
+	RequestIDHeader = "X-Request-Id"
 
 	// GrpcAccessKeyHeader is the metadata key for access key authentication in gRPC calls
 	GrpcAccessKeyHeader = "x-api-token"
@@ -47,4 +49,3 @@ const (
 	// GrpcHostIDKey is the metadata key for host ID in gRPC calls
 	GrpcHostIDKey = "host-id"
 )
-
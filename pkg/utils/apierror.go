@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	v1 "github.com/kubescape/backend/pkg/server/v1"
+)
+
+var (
+	// ErrUnauthorized matches API errors caused by missing or rejected credentials (HTTP 401 or 403).
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound matches API errors caused by a missing resource (HTTP 404).
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited matches API errors caused by the backend throttling requests (HTTP 429).
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// maxAPIErrorBody caps the length of the response body retained in an APIError.
+const maxAPIErrorBody = 1024
+
+// APIError is an error response from the backend.
+//
+// Callers may use errors.Is to match the sentinel errors ErrUnauthorized, ErrNotFound and ErrRateLimited,
+// or errors.As to inspect the response.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Status is the HTTP status line of the response, e.g. "404 Not Found"
+	Status string
+	// URL is the URL of the request
+	URL string
+	// Body holds the error message returned by the backend, capped to 1KB
+	Body string
+	// RequestID is the request identifier assigned by the backend, if any
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "http-error: '%s', reason: '%s'", e.Status, e.Body)
+	if e.URL != "" {
+		fmt.Fprintf(&b, ", url: '%s'", e.URL)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, ", request-id: '%s'", e.RequestID)
+	}
+
+	return b.String()
+}
+
+// Is matches the sentinel error corresponding to the status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// ErrAPI reports an API error as an *APIError, with a cap on the length of the error message.
+//
+// The response body is consumed and closed.
+func ErrAPI(resp *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  resp.Header.Get(v1.RequestIDHeader),
+	}
+
+	if resp.Request != nil && resp.Request.URL != nil {
+		apiErr.URL = resp.Request.URL.Redacted()
+	}
+
+	if resp.Body != nil {
+		defer resp.Body.Close()
+
+		reason, _ := io.ReadAll(io.LimitReader(resp.Body, maxAPIErrorBody))
+		apiErr.Body = strings.TrimSpace(string(reason))
+	}
+
+	return apiErr
+}
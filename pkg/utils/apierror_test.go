@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	v1 "github.com/kubescape/backend/pkg/server/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrAPI(t *testing.T) {
+	t.Parallel()
+
+	newResponse := func(status int, body string) *http.Response {
+		return &http.Response{
+			StatusCode:    status,
+			Status:        http.StatusText(status),
+			Header:        http.Header{v1.RequestIDHeader: []string{"req-1"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: -1,
+			Request: &http.Request{
+				URL: &url.URL{Scheme: "https", Host: "api.example.com", Path: "/api/v1/frameworks"},
+			},
+		}
+	}
+
+	t.Run("should capture the response", func(t *testing.T) {
+		t.Parallel()
+
+		err := ErrAPI(newResponse(http.StatusNotFound, "no such framework\n"))
+
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		require.Equal(t, "no such framework", apiErr.Body)
+		require.Equal(t, "https://api.example.com/api/v1/frameworks", apiErr.URL)
+		require.Equal(t, "req-1", apiErr.RequestID)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.NotErrorIs(t, err, ErrUnauthorized)
+		require.Contains(t, err.Error(), "http-error")
+		require.Contains(t, err.Error(), "req-1")
+	})
+
+	t.Run("should cap the length of the body", func(t *testing.T) {
+		t.Parallel()
+
+		err := ErrAPI(newResponse(http.StatusTooManyRequests, strings.Repeat("x", 2*maxAPIErrorBody)))
+
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		require.Len(t, apiErr.Body, maxAPIErrorBody)
+		require.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("should tolerate a response without body", func(t *testing.T) {
+		t.Parallel()
+
+		resp := newResponse(http.StatusUnauthorized, "")
+		resp.Body = nil
+		resp.Request = nil
+		resp.Header = http.Header{}
+
+		err := ErrAPI(resp)
+		require.ErrorIs(t, err, ErrUnauthorized)
+		require.Equal(t, "http-error: 'Unauthorized', reason: ''", err.Error())
+	})
+}
@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// AccountEnvVar is the environment variable holding the account ID.
+	AccountEnvVar = "KS_ACCOUNT_ID"
+	// AccessKeyEnvVar is the environment variable holding the access key.
+	AccessKeyEnvVar = "KS_ACCESS_KEY"
+)
+
+var (
+	_ ICredentialsProvider = &StaticCredentialsProvider{}
+	_ ICredentialsProvider = &EnvCredentialsProvider{}
+	_ ICredentialsProvider = &SecretDirCredentialsProvider{}
+	_ ICredentialsProvider = &KubernetesSecretCredentialsProvider{}
+	_ ICredentialsProvider = &ConfigFileCredentialsProvider{}
+)
+
+// ICredentialsProvider supplies credentials from a source. Credentials may be partial, or empty
+// if the source is not available (e.g. a missing or forbidden file or secret).
+type ICredentialsProvider interface {
+	// Name identifies the source of the credentials.
+	Name() string
+	Credentials(ctx context.Context) (*Credentials, error)
+}
+
+// ResolvedCredentials are credentials resolved from a chain of providers, with the source of each field.
+type ResolvedCredentials struct {
+	Credentials
+
+	// AccountSource is the name of the provider that supplied the account, if any.
+	AccountSource string
+	// AccessKeySource is the name of the provider that supplied the access key, if any.
+	AccessKeySource string
+}
+
+// ResolveCredentials builds credentials from an ordered chain of providers.
+//
+// Each field is taken from the first provider that supplies it. Providers are not called
+// once all fields are resolved. Unavailable sources (e.g. a missing or forbidden file or secret) are skipped.
+//
+// A provider which fails otherwise is skipped as well, but its error is reported: the credentials resolved
+// from the other providers are returned along with the errors of all the failing providers, joined.
+//
+// A typical chain is: explicit values, environment variables, a mounted secret directory,
+// a Kubernetes Secret, and finally the local configuration file.
+func ResolveCredentials(ctx context.Context, providers ...ICredentialsProvider) (*ResolvedCredentials, error) {
+	resolved := &ResolvedCredentials{}
+
+	var errs []error
+
+	for _, provider := range providers {
+		if resolved.Account != "" && resolved.AccessKey != "" {
+			break
+		}
+
+		credentials, err := provider.Credentials(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolving credentials from %s: %w", provider.Name(), err))
+
+			continue
+		}
+
+		if credentials == nil {
+			continue
+		}
+
+		if resolved.Account == "" && credentials.Account != "" {
+			resolved.Account = credentials.Account
+			resolved.AccountSource = provider.Name()
+		}
+
+		if resolved.AccessKey == "" && credentials.AccessKey != "" {
+			resolved.AccessKey = credentials.AccessKey
+			resolved.AccessKeySource = provider.Name()
+		}
+	}
+
+	return resolved, errors.Join(errs...)
+}
+
+// StaticCredentialsProvider supplies explicit credentials, e.g. from command line flags.
+type StaticCredentialsProvider struct {
+	credentials Credentials
+}
+
+// NewStaticCredentialsProvider builds a provider of explicit credentials. Empty values are not supplied.
+func NewStaticCredentialsProvider(account, accessKey string) *StaticCredentialsProvider {
+	return &StaticCredentialsProvider{
+		credentials: Credentials{Account: account, AccessKey: accessKey},
+	}
+}
+
+func (p *StaticCredentialsProvider) Name() string { return "explicit" }
+
+func (p *StaticCredentialsProvider) Credentials(_ context.Context) (*Credentials, error) {
+	credentials := p.credentials
+
+	return &credentials, nil
+}
+
+// EnvCredentialsProvider supplies credentials from the KS_ACCOUNT_ID and KS_ACCESS_KEY environment variables.
+type EnvCredentialsProvider struct{}
+
+// NewEnvCredentialsProvider builds a provider of credentials from environment variables.
+func NewEnvCredentialsProvider() *EnvCredentialsProvider {
+	return &EnvCredentialsProvider{}
+}
+
+func (p *EnvCredentialsProvider) Name() string { return "env" }
+
+func (p *EnvCredentialsProvider) Credentials(_ context.Context) (*Credentials, error) {
+	return &Credentials{
+		Account:   os.Getenv(AccountEnvVar),
+		AccessKey: os.Getenv(AccessKeyEnvVar),
+	}, nil
+}
+
+// SecretDirCredentialsProvider supplies credentials from a mounted secret directory.
+type SecretDirCredentialsProvider struct {
+	secretPath string
+}
+
+// NewSecretDirCredentialsProvider builds a provider of credentials from a mounted secret directory.
+func NewSecretDirCredentialsProvider(secretPath string) *SecretDirCredentialsProvider {
+	return &SecretDirCredentialsProvider{secretPath: secretPath}
+}
+
+func (p *SecretDirCredentialsProvider) Name() string { return "secret:" + p.secretPath }
+
+func (p *SecretDirCredentialsProvider) Credentials(_ context.Context) (*Credentials, error) {
+	if _, err := os.Stat(p.secretPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	credentials, err := LoadCredentialsFromFile(p.secretPath)
+	if err != nil {
+		// the directory holds none of the expected files
+		return nil, nil
+	}
+
+	return credentials, nil
+}
+
+// KubernetesSecretCredentialsProvider supplies credentials from a Kubernetes Secret, read from the API server.
+type KubernetesSecretCredentialsProvider struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewKubernetesSecretCredentialsProvider builds a provider of credentials from a Kubernetes Secret,
+// holding the "account" and "accessKey" keys.
+func NewKubernetesSecretCredentialsProvider(client kubernetes.Interface, namespace, name string) *KubernetesSecretCredentialsProvider {
+	return &KubernetesSecretCredentialsProvider{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+func (p *KubernetesSecretCredentialsProvider) Name() string {
+	return "kubernetes-secret:" + p.namespace + "/" + p.name
+}
+
+func (p *KubernetesSecretCredentialsProvider) Credentials(ctx context.Context) (*Credentials, error) {
+	secret, err := p.client.CoreV1().Secrets(p.namespace).Get(ctx, p.name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err) {
+			// the secret is missing, or not readable with the permissions of the caller
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &Credentials{
+		Account:   strings.TrimSuffix(string(secret.Data[AccountSecretKey]), "\n"),
+		AccessKey: strings.TrimSuffix(string(secret.Data[AccessKeySecretKey]), "\n"),
+	}, nil
+}
+
+// ConfigFileCredentialsProvider supplies credentials from a local configuration file, such as ~/.kubescape/config.json.
+type ConfigFileCredentialsProvider struct {
+	path string
+}
+
+// NewConfigFileCredentialsProvider builds a provider of credentials from a local configuration file.
+//
+// An empty path stands for the default configuration file (see DefaultConfigFilePath).
+func NewConfigFileCredentialsProvider(path string) *ConfigFileCredentialsProvider {
+	if path == "" {
+		// without a home directory, there is no default configuration file
+		path, _ = DefaultConfigFilePath()
+	}
+
+	return &ConfigFileCredentialsProvider{path: path}
+}
+
+// DefaultConfigFilePath yields the path to the local configuration file of kubescape: ~/.kubescape/config.json.
+func DefaultConfigFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".kubescape", "config.json"), nil
+}
+
+func (p *ConfigFileCredentialsProvider) Name() string { return "config:" + p.path }
+
+func (p *ConfigFileCredentialsProvider) Credentials(_ context.Context) (*Credentials, error) {
+	if p.path == "" {
+		return nil, nil
+	}
+
+	buf, err := os.ReadFile(p.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var config struct {
+		AccountID string `json:"accountID"`
+		AccessKey string `json:"accessKey"`
+	}
+	if err := json.Unmarshal(buf, &config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", p.path, err)
+	}
+
+	return &Credentials{
+		Account:   config.AccountID,
+		AccessKey: config.AccessKey,
+	}, nil
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestResolveCredentials(t *testing.T) {
+	secretPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(secretPath, AccessKeySecretKey), []byte("secret-key\n"), 0o600))
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"accountID":"config-account","accessKey":"config-key"}`), 0o600))
+
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kubescape", Name: "cloud-secret"},
+		Data: map[string][]byte{
+			AccountSecretKey:   []byte("k8s-account"),
+			AccessKeySecretKey: []byte("k8s-key"),
+		},
+	})
+
+	ctx := context.Background()
+
+	t.Run("should take each field from the first provider supplying it", func(t *testing.T) {
+		t.Setenv(AccountEnvVar, "env-account")
+		t.Setenv(AccessKeyEnvVar, "")
+
+		resolved, err := ResolveCredentials(ctx,
+			NewStaticCredentialsProvider("", ""),
+			NewEnvCredentialsProvider(),
+			NewSecretDirCredentialsProvider(secretPath),
+			NewKubernetesSecretCredentialsProvider(clientset, "kubescape", "cloud-secret"),
+			NewConfigFileCredentialsProvider(configPath),
+		)
+		require.NoError(t, err)
+		require.Equal(t, Credentials{Account: "env-account", AccessKey: "secret-key"}, resolved.Credentials)
+		require.Equal(t, "env", resolved.AccountSource)
+		require.Equal(t, "secret:"+secretPath, resolved.AccessKeySource)
+	})
+
+	t.Run("should favor explicit values", func(t *testing.T) {
+		t.Setenv(AccountEnvVar, "env-account")
+
+		resolved, err := ResolveCredentials(ctx,
+			NewStaticCredentialsProvider("flag-account", "flag-key"),
+			NewEnvCredentialsProvider(),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "explicit", resolved.AccountSource)
+		require.Equal(t, "explicit", resolved.AccessKeySource)
+	})
+
+	t.Run("should skip unavailable sources", func(t *testing.T) {
+		t.Setenv(AccountEnvVar, "")
+		t.Setenv(AccessKeyEnvVar, "")
+
+		resolved, err := ResolveCredentials(ctx,
+			NewEnvCredentialsProvider(),
+			NewSecretDirCredentialsProvider(filepath.Join(t.TempDir(), "missing")),
+			NewKubernetesSecretCredentialsProvider(clientset, "kubescape", "missing-secret"),
+			NewKubernetesSecretCredentialsProvider(clientset, "kubescape", "cloud-secret"),
+			NewConfigFileCredentialsProvider(configPath),
+		)
+		require.NoError(t, err)
+		require.Equal(t, Credentials{Account: "k8s-account", AccessKey: "k8s-key"}, resolved.Credentials)
+		require.Equal(t, "kubernetes-secret:kubescape/cloud-secret", resolved.AccountSource)
+
+		resolved, err = ResolveCredentials(ctx,
+			NewConfigFileCredentialsProvider(filepath.Join(t.TempDir(), "missing.json")),
+			NewConfigFileCredentialsProvider(configPath),
+		)
+		require.NoError(t, err)
+		require.Equal(t, Credentials{Account: "config-account", AccessKey: "config-key"}, resolved.Credentials)
+		require.Equal(t, "config:"+configPath, resolved.AccessKeySource)
+	})
+
+	t.Run("should leave unresolved fields empty", func(t *testing.T) {
+		resolved, err := ResolveCredentials(ctx, NewStaticCredentialsProvider("", ""))
+		require.NoError(t, err)
+		require.Empty(t, resolved.Account)
+		require.Empty(t, resolved.AccountSource)
+	})
+
+	t.Run("should skip forbidden secrets", func(t *testing.T) {
+		forbidden := fake.NewSimpleClientset()
+		forbidden.PrependReactor("get", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, k8serrors.NewForbidden(corev1.Resource("secrets"), "cloud-secret", errors.New("RBAC denied"))
+		})
+
+		resolved, err := ResolveCredentials(ctx,
+			NewKubernetesSecretCredentialsProvider(forbidden, "kubescape", "cloud-secret"),
+			NewConfigFileCredentialsProvider(configPath),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "config:"+configPath, resolved.AccountSource)
+	})
+
+	t.Run("should skip failing providers, and report their errors", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(invalid, []byte(`{`), 0o600))
+
+		resolved, err := ResolveCredentials(ctx,
+			NewConfigFileCredentialsProvider(invalid),
+			NewKubernetesSecretCredentialsProvider(clientset, "kubescape", "cloud-secret"),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "config:"+invalid)
+		require.NotNil(t, resolved)
+		require.Equal(t, Credentials{Account: "k8s-account", AccessKey: "k8s-key"}, resolved.Credentials)
+	})
+
+	t.Run("should report provider errors when all providers fail", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(invalid, []byte(`{`), 0o600))
+
+		unreachable := fake.NewSimpleClientset()
+		errUnreachable := errors.New("connection refused")
+		unreachable.PrependReactor("get", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errUnreachable
+		})
+
+		_, err := ResolveCredentials(ctx,
+			NewConfigFileCredentialsProvider(invalid),
+			NewKubernetesSecretCredentialsProvider(unreachable, "kubescape", "cloud-secret"),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "config:"+invalid)
+		require.Contains(t, err.Error(), "kubernetes-secret:kubescape/cloud-secret")
+		require.ErrorIs(t, err, errUnreachable)
+	})
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ErrWatcherStarted is returned when starting a CredentialsWatcher which was already started.
+var ErrWatcherStarted = errors.New("credentials watcher already started")
+
+// CredentialsWatcher reloads the credentials held in a mounted secret directory whenever they change.
+//
+// The whole directory is watched rather than individual files: Kubernetes updates mounted secrets
+// by atomically swapping the "..data" symlink to a new directory, which replaces all files at once.
+type CredentialsWatcher struct {
+	secretPath string
+	updates    chan Credentials
+	started    atomic.Bool
+
+	mu        sync.RWMutex
+	current   Credentials
+	callbacks []func(Credentials)
+}
+
+// NewCredentialsWatcher loads the credentials from a mounted secret directory, and prepares to watch them.
+//
+// Call Start to begin watching.
+func NewCredentialsWatcher(secretPath string) (*CredentialsWatcher, error) {
+	credentials, err := LoadCredentialsFromFile(secretPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CredentialsWatcher{
+		secretPath: secretPath,
+		updates:    make(chan Credentials, 1),
+		current:    *credentials,
+	}, nil
+}
+
+// Current yields the latest credentials.
+func (w *CredentialsWatcher) Current() Credentials {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.current
+}
+
+// Updates yields a channel that receives the credentials whenever they change.
+//
+// Only the latest update is retained if the channel is not drained. The channel is closed when the watcher stops.
+func (w *CredentialsWatcher) Updates() <-chan Credentials {
+	return w.updates
+}
+
+// OnUpdate registers a callback, called with the new credentials whenever they change.
+//
+// Callbacks are called sequentially, from the watching goroutine.
+func (w *CredentialsWatcher) OnUpdate(callback func(Credentials)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.callbacks = append(w.callbacks, callback)
+}
+
+// Start watches the secret directory in the background, until the context is cancelled.
+//
+// A watcher may only be started once, even after it stopped: later calls return ErrWatcherStarted.
+func (w *CredentialsWatcher) Start(ctx context.Context) error {
+	if !w.started.CompareAndSwap(false, true) {
+		return ErrWatcherStarted
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		w.started.Store(false)
+
+		return fmt.Errorf("watching credentials: %w", err)
+	}
+
+	if err := watcher.Add(w.secretPath); err != nil {
+		_ = watcher.Close()
+		w.started.Store(false)
+
+		return fmt.Errorf("watching credentials in path %s: %w", w.secretPath, err)
+	}
+
+	go w.run(ctx, watcher)
+
+	return nil
+}
+
+func (w *CredentialsWatcher) run(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer close(w.updates)
+	defer watcher.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			w.reload()
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			// events may have been dropped: reload to be safe
+			w.reload()
+		}
+	}
+}
+
+// reload reads the credentials again and notifies changes.
+//
+// Read errors are ignored, as the directory may be in the middle of an update: the next event retries.
+func (w *CredentialsWatcher) reload() {
+	credentials, err := LoadCredentialsFromFile(w.secretPath)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	if *credentials == w.current {
+		w.mu.Unlock()
+
+		return
+	}
+	w.current = *credentials
+	callbacks := w.callbacks
+	w.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(*credentials)
+	}
+
+	// retain only the latest update
+	select {
+	case <-w.updates:
+	default:
+	}
+	w.updates <- *credentials
+}
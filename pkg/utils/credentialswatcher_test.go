@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeSecretVersion mimics how the kubelet updates a mounted secret: a new timestamped directory is written,
+// then the "..data" symlink is atomically swapped to point to it.
+func writeSecretVersion(t testing.TB, secretPath, version string, credentials Credentials) {
+	versionDir := filepath.Join(secretPath, version)
+	require.NoError(t, os.MkdirAll(versionDir, 0o750))
+
+	require.NoError(t, os.WriteFile(filepath.Join(versionDir, AccessKeySecretKey), []byte(credentials.AccessKey), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(versionDir, AccountSecretKey), []byte(credentials.Account), 0o600))
+
+	tmpLink := filepath.Join(secretPath, "..data_tmp")
+	require.NoError(t, os.Symlink(version, tmpLink))
+	require.NoError(t, os.Rename(tmpLink, filepath.Join(secretPath, "..data")))
+
+	for _, key := range []string{AccessKeySecretKey, AccountSecretKey} {
+		link := filepath.Join(secretPath, key)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			require.NoError(t, os.Symlink(filepath.Join("..data", key), link))
+		}
+	}
+}
+
+func TestCredentialsWatcher(t *testing.T) {
+	t.Parallel()
+
+	secretPath := t.TempDir()
+	initial := Credentials{Account: "account-1", AccessKey: "key-1"}
+	writeSecretVersion(t, secretPath, "..2025_01_01_00_00_00.1", initial)
+
+	watcher, err := NewCredentialsWatcher(secretPath)
+	require.NoError(t, err)
+	require.Equal(t, initial, watcher.Current())
+
+	callbacks := make(chan Credentials, 10)
+	watcher.OnUpdate(func(credentials Credentials) {
+		callbacks <- credentials
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, watcher.Start(ctx))
+
+	t.Run("should refuse to start twice", func(t *testing.T) {
+		require.ErrorIs(t, watcher.Start(ctx), ErrWatcherStarted)
+	})
+
+	t.Run("should notify a rotated secret", func(t *testing.T) {
+		rotated := Credentials{Account: "account-1", AccessKey: "key-2"}
+		writeSecretVersion(t, secretPath, "..2025_01_02_00_00_00.2", rotated)
+		require.NoError(t, os.RemoveAll(filepath.Join(secretPath, "..2025_01_01_00_00_00.1")))
+
+		select {
+		case update := <-watcher.Updates():
+			require.Equal(t, rotated, update)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "expected a credentials update")
+		}
+
+		require.Equal(t, rotated, <-callbacks)
+		require.Equal(t, rotated, watcher.Current())
+	})
+
+	t.Run("should close the updates channel when stopped", func(t *testing.T) {
+		cancel()
+
+		require.Eventually(t, func() bool {
+			select {
+			case _, ok := <-watcher.Updates():
+				return !ok
+			default:
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.ErrorIs(t, watcher.Start(context.Background()), ErrWatcherStarted)
+	})
+}
+
+func TestCredentialsWatcherErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCredentialsWatcher(t.TempDir())
+	require.Error(t, err)
+}
@@ -1,9 +1,7 @@
 package utils
 
 import (
-	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"strings"
 )
@@ -48,24 +46,6 @@ func ParseHost(host string) (string, string, error) {
 	return "https", strings.Replace(host, "https://", "", 1), nil
 }
 
-// ErrAPI reports an API error, with a cap on the length of the error message.
-func ErrAPI(resp *http.Response) error {
-	const maxSize = 1024
-
-	reason := new(strings.Builder)
-	if resp.Body != nil {
-		size := min(resp.ContentLength, maxSize)
-		if size > 0 {
-			reason.Grow(int(size))
-		}
-
-		_, _ = io.CopyN(reason, resp.Body, size)
-		defer resp.Body.Close()
-	}
-
-	return fmt.Errorf("http-error: '%s', reason: '%s'", resp.Status, reason.String())
-}
-
 func IsNativeFramework(framework string) bool {
 	return contains([]string{"allcontrols", "nsa", "mitre"}, framework)
 }